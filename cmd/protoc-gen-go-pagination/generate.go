@@ -0,0 +1,506 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.github.com/uptutu/pagination"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	paginationPackage = protogen.GoImportPath("github.github.com/uptutu/pagination")
+	protoPackage      = protogen.GoImportPath("google.golang.org/protobuf/proto")
+	wrappersPackage   = protogen.GoImportPath("google.golang.org/protobuf/types/known/wrapperspb")
+	strconvPackage    = protogen.GoImportPath("strconv")
+)
+
+var (
+	_requestFields  = []string{"page", "pagination", "page_request", "pagination_request"}
+	_responseFields = []string{"page", "pagination"}
+)
+
+// requestField is a field of pagination.Page set from a request message.
+type requestField struct {
+	field   pagination.PageField
+	names   []string
+	page    string // field of pagination.Page
+	kind    protoreflect.Kind
+	invalid string // error of pagination returned for numeric strings that don't parse
+}
+
+var _requestFieldsOrder = []requestField{
+	{pagination.PageField_PAGE_NUM, []string{"page_num", "num"}, "Num", protoreflect.Int64Kind, "ErrInvalidPageNum"},
+	{pagination.PageField_PAGE_SIZE, []string{"page_size", "size"}, "Size", protoreflect.Int64Kind, "ErrInvalidPageSize"},
+	{pagination.PageField_ORDER_BY, []string{"order_by"}, "OrderBy", protoreflect.StringKind, ""},
+	{pagination.PageField_IS_DESCENDING, []string{"is_descending", "descending"}, "IsDescending", protoreflect.BoolKind, ""},
+	{pagination.PageField_QUERY, []string{"query", "search_key"}, "Query", protoreflect.StringKind, ""},
+	{pagination.PageField_PAGE_TOKEN, []string{"page_token"}, "Token", protoreflect.StringKind, ""},
+	{pagination.PageField_FILTER, []string{"filter"}, "Filter", protoreflect.StringKind, ""},
+	{pagination.PageField_SHOW_TOTAL, []string{"show_total"}, "ShowTotal", protoreflect.BoolKind, ""},
+}
+
+// responseField is a field of a response message set from pagination.Meta.
+type responseField struct {
+	names    []string
+	meta     string // field of pagination.Meta
+	kind     protoreflect.Kind
+	optional bool // the Meta field is a pointer
+}
+
+var _responseFieldsOrder = []responseField{
+	{[]string{"total"}, "Total", protoreflect.Int64Kind, true},
+	{[]string{"page_num", "num", "current_page"}, "PageNum", protoreflect.Int64Kind, false},
+	{[]string{"page_size", "size"}, "PageSize", protoreflect.Int64Kind, false},
+	{[]string{"last_page"}, "LastPage", protoreflect.Int64Kind, true},
+	{[]string{"total_pages"}, "LastPage", protoreflect.Int64Kind, true},
+	{[]string{"has_next"}, "HasNext", protoreflect.BoolKind, false},
+	{[]string{"next_page_token"}, "NextPageToken", protoreflect.StringKind, false},
+	{[]string{"total_is_capped", "total_capped"}, "TotalIsCapped", protoreflect.BoolKind, false},
+	{[]string{"total_is_estimate", "is_estimate"}, "TotalIsEstimate", protoreflect.BoolKind, false},
+}
+
+// generateFile generates the adapters of the messages of f into
+// <name>_pagination.pb.go, nothing when f has no pagination messages.
+func generateFile(gen *protogen.Plugin, f *protogen.File) error {
+	out := gen.NewGeneratedFile(f.GeneratedFilenamePrefix+"_pagination.pb.go", f.GoImportPath)
+	out.P("// Code generated by protoc-gen-go-pagination. DO NOT EDIT.")
+	out.P("// source: ", f.Desc.Path())
+	out.P()
+	out.P("package ", f.GoPackageName)
+	out.P()
+
+	g := &generator{GeneratedFile: out, file: f}
+	var walk func([]*protogen.Message) error
+	walk = func(ms []*protogen.Message) error {
+		for _, m := range ms {
+			if m.Desc.IsMapEntry() {
+				continue
+			}
+			if err := g.message(m); err != nil {
+				return err
+			}
+			if err := walk(m.Messages); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(f.Messages); err != nil {
+		return err
+	}
+	if !g.generated {
+		out.Skip()
+	}
+	return nil
+}
+
+type generator struct {
+	*protogen.GeneratedFile
+	file      *protogen.File
+	generated bool
+}
+
+// ident returns the qualified name of an identifier of another package.
+func (g *generator) ident(path protogen.GoImportPath, name string) string {
+	return g.QualifiedGoIdent(protogen.GoIdent{GoName: name, GoImportPath: path})
+}
+
+func (g *generator) message(m *protogen.Message) error {
+	switch {
+	case isResponse(m):
+		g.generated = true
+		return g.response(m)
+	case isRequest(m):
+		g.generated = true
+		return g.request(m)
+	}
+	if f := findField(m, _responseFields...); f != nil && f.Message != nil && g.local(f.Message) && isResponse(f.Message) {
+		g.generated = true
+		g.nestedResponse(m, f)
+	} else if f := findField(m, _requestFields...); f != nil && f.Message != nil && g.local(f.Message) && isRequest(f.Message) {
+		g.generated = true
+		g.nestedRequest(m, f)
+	}
+	return nil
+}
+
+// local reports whether m is generated into the same file.
+func (g *generator) local(m *protogen.Message) bool {
+	return m.Desc.ParentFile().Path() == g.file.Desc.Path()
+}
+
+func (g *generator) request(m *protogen.Message) error {
+	name := m.GoIdent.GoName
+	options := g.policy(m)
+
+	g.P("// ToPage returns the page requested by x.")
+	if len(options) > 0 {
+		g.P("// The policy of ", name, " is applied before options.")
+	}
+	g.P("func (x *", name, ") ToPage(options ...", g.ident(paginationPackage, "Option"), ") (", g.ident(paginationPackage, "Page"), ", error) {")
+	fields := map[string]*protogen.Field{}
+	for _, rf := range _requestFieldsOrder {
+		f := markedField(m, rf.field)
+		if f == nil {
+			// fields marked for another pagination field aren't matched by name
+			if f = findField(m, rf.names...); f != nil && pageField(f) != pagination.PageField_PAGE_FIELD_UNSPECIFIED {
+				f = nil
+			}
+		}
+		if f != nil {
+			fields[rf.page] = f
+		}
+	}
+	exprs := map[string]string{}
+	for _, rf := range _requestFieldsOrder {
+		f := fields[rf.page]
+		if f == nil {
+			continue
+		}
+		expr, parse, err := g.get(f, rf.kind)
+		if err != nil {
+			return err
+		}
+		if parse {
+			// numeric strings are parsed like Parse does
+			v := strings.ToLower(rf.page)
+			g.P(v, ", err := ", g.ident(paginationPackage, "ParseInt"), "(", expr, ", ", g.ident(paginationPackage, rf.invalid), ")")
+			g.P("if err != nil {")
+			g.P("return ", g.ident(paginationPackage, "Page"), "{}, err")
+			g.P("}")
+			expr = v
+		}
+		exprs[rf.page] = expr
+	}
+	sort := sortField(m)
+	if sort != nil {
+		// sort takes precedence over order_by like in ParseProto
+		orderBy, ok := exprs["OrderBy"]
+		if !ok {
+			orderBy = `""`
+		}
+		g.P("orderBy, err := ", g.ident(paginationPackage, "SortOrderBy"), "(x.Get", sort.GoName, "(), ", orderBy, ")")
+		g.P("if err != nil {")
+		g.P("return ", g.ident(paginationPackage, "Page"), "{}, err")
+		g.P("}")
+		exprs["OrderBy"] = "orderBy"
+	}
+	g.P("return ", g.ident(paginationPackage, "New"), "(", g.ident(paginationPackage, "Page"), "{")
+	for _, rf := range _requestFieldsOrder {
+		if expr, ok := exprs[rf.page]; ok {
+			g.P(rf.page, ": ", expr, ",")
+		}
+	}
+	g.P("}, ", g.optionsExpr(options), ")")
+	g.P("}")
+	g.P()
+
+	g.P("// ApplyPage sets the pagination fields of x from p.")
+	g.P("func (x *", name, ") ApplyPage(p ", g.ident(paginationPackage, "Page"), ") {")
+	for _, rf := range _requestFieldsOrder {
+		f := fields[rf.page]
+		if f == nil {
+			continue
+		}
+		expr, err := g.set(f, rf.kind, "p."+rf.page, "int")
+		if err != nil {
+			return err
+		}
+		g.P("x.", f.GoName, " = ", expr)
+	}
+	switch {
+	case sort != nil && fields["OrderBy"] != nil:
+		// order_by holds the order, sort would take precedence over it
+		g.P("x.", sort.GoName, " = nil")
+	case sort != nil:
+		g.P("x.", sort.GoName, " = p.SortFields()")
+	}
+	g.P("}")
+	g.P()
+	return nil
+}
+
+// policy generates the constants of the (pagination.policy) option of m and
+// returns the options applying it.
+func (g *generator) policy(m *protogen.Message) []string {
+	policy, _ := proto.GetExtension(m.Desc.Options(), pagination.E_Policy).(*pagination.PagePolicy)
+	if policy == nil {
+		return nil
+	}
+	name := m.GoIdent.GoName
+	var options []string
+	if policy.GetDefaultSize() > 0 || policy.GetMaxSize() > 0 {
+		g.P("// Policy of ", name, ", see its (pagination.policy) option.")
+		g.P("const (")
+		if policy.GetDefaultSize() > 0 {
+			g.P(name, "DefaultSize = ", policy.GetDefaultSize())
+			options = append(options, g.ident(paginationPackage, "WithDefaultSize")+"("+name+"DefaultSize)")
+		}
+		if policy.GetMaxSize() > 0 {
+			g.P(name, "MaxSize = ", policy.GetMaxSize())
+			options = append(options, g.ident(paginationPackage, "WithMaxSize")+"("+name+"MaxSize)")
+		}
+		g.P(")")
+		g.P()
+	}
+	if len(policy.GetSortable()) > 0 {
+		quoted := make([]string, len(policy.GetSortable()))
+		for i, s := range policy.GetSortable() {
+			quoted[i] = strconv.Quote(s)
+		}
+		g.P("// ", name, "Sortable lists the fields ", name, " can be ordered by.")
+		g.P("var ", name, "Sortable = []string{", strings.Join(quoted, ", "), "}")
+		g.P()
+		options = append(options, g.ident(paginationPackage, "WithSortable")+"("+name+"Sortable...)")
+	}
+	return options
+}
+
+// optionsExpr returns the options argument passing options before the
+// options of the caller.
+func (g *generator) optionsExpr(options []string) string {
+	if len(options) == 0 {
+		return "options..."
+	}
+	return "append([]" + g.ident(paginationPackage, "Option") + "{\n" + strings.Join(options, ",\n") + ",\n}, options...)..."
+}
+
+func (g *generator) response(m *protogen.Message) error {
+	g.P("// ApplyPage fills x with the metadata of p, like FillResponse does.")
+	g.P("func (x *", m.GoIdent.GoName, ") ApplyPage(p ", g.ident(paginationPackage, "Page"), ") {")
+	g.P("m := p.Meta()")
+	for _, rf := range _responseFieldsOrder {
+		f := findField(m, rf.names...)
+		if f == nil {
+			continue
+		}
+		value := "m." + rf.meta
+		if rf.optional {
+			value = "*" + value
+		}
+		expr, err := g.set(f, rf.kind, value, "int64")
+		if err != nil {
+			return err
+		}
+		if rf.optional {
+			g.P("if m.", rf.meta, " != nil {")
+			g.P("x.", f.GoName, " = ", expr)
+			g.P("}")
+			continue
+		}
+		g.P("x.", f.GoName, " = ", expr)
+	}
+	g.P("}")
+	g.P()
+	return nil
+}
+
+func (g *generator) nestedRequest(m *protogen.Message, f *protogen.Field) {
+	options := g.policy(m)
+	g.P("// ToPage returns the page requested by the ", f.Desc.Name(), " field of x.")
+	if len(options) > 0 {
+		g.P("// The policy of ", m.GoIdent.GoName, " is applied before options.")
+	}
+	g.P("func (x *", m.GoIdent.GoName, ") ToPage(options ...", g.ident(paginationPackage, "Option"), ") (", g.ident(paginationPackage, "Page"), ", error) {")
+	g.P("return x.Get", f.GoName, "().ToPage(", g.optionsExpr(options), ")")
+	g.P("}")
+	g.P()
+	g.P("// ApplyPage sets the ", f.Desc.Name(), " field of x from p.")
+	g.P("func (x *", m.GoIdent.GoName, ") ApplyPage(p ", g.ident(paginationPackage, "Page"), ") {")
+	g.nestedApply(f)
+	g.P("}")
+	g.P()
+}
+
+func (g *generator) nestedResponse(m *protogen.Message, f *protogen.Field) {
+	g.P("// ApplyPage fills the ", f.Desc.Name(), " field of x with the metadata of p.")
+	g.P("func (x *", m.GoIdent.GoName, ") ApplyPage(p ", g.ident(paginationPackage, "Page"), ") {")
+	g.nestedApply(f)
+	g.P("}")
+	g.P()
+}
+
+func (g *generator) nestedApply(f *protogen.Field) {
+	g.P("if x.", f.GoName, " == nil {")
+	g.P("x.", f.GoName, " = &", f.Message.GoIdent.GoName, "{}")
+	g.P("}")
+	g.P("x.", f.GoName, ".ApplyPage(p)")
+}
+
+// get returns the expression reading f of x as kind, which is int for
+// numbers. parse is true when the expression is a string holding a number,
+// which has to be parsed by pagination.ParseInt.
+func (g *generator) get(f *protogen.Field, kind protoreflect.Kind) (expr string, parse bool, err error) {
+	expr = "x.Get" + f.GoName + "()"
+	fieldKind, wrapper, err := fieldKind(f)
+	if err != nil {
+		return "", false, err
+	}
+	if wrapper != "" {
+		expr += ".GetValue()"
+	}
+	switch {
+	case kind == protoreflect.Int64Kind && isNumber(fieldKind):
+		return "int(" + expr + ")", false, nil
+	case kind == protoreflect.Int64Kind && fieldKind == protoreflect.StringKind:
+		return expr, true, nil
+	case kind == fieldKind:
+		return expr, false, nil
+	}
+	return "", false, fmt.Errorf("%s: a %s field can't hold %s", f.Desc.FullName(), fieldKind, kind)
+}
+
+// set returns the expression of value, a bool, string or number of kind,
+// converted to the type of f. numberType is the Go type of numbers.
+func (g *generator) set(f *protogen.Field, kind protoreflect.Kind, value, numberType string) (string, error) {
+	fieldKind, wrapper, err := fieldKind(f)
+	if err != nil {
+		return "", err
+	}
+	var expr, pointer string
+	switch {
+	case kind == protoreflect.Int64Kind && fieldKind == protoreflect.StringKind:
+		if numberType != "int64" {
+			value = "int64(" + value + ")"
+		}
+		expr, pointer = g.ident(strconvPackage, "FormatInt")+"("+value+", 10)", "String"
+	case kind == protoreflect.Int64Kind && isNumber(fieldKind):
+		typ := map[protoreflect.Kind]string{
+			protoreflect.Int32Kind: "int32", protoreflect.Sint32Kind: "int32", protoreflect.Sfixed32Kind: "int32",
+			protoreflect.Int64Kind: "int64", protoreflect.Sint64Kind: "int64", protoreflect.Sfixed64Kind: "int64",
+			protoreflect.Uint32Kind: "uint32", protoreflect.Fixed32Kind: "uint32",
+			protoreflect.Uint64Kind: "uint64", protoreflect.Fixed64Kind: "uint64",
+			protoreflect.FloatKind: "float32", protoreflect.DoubleKind: "float64",
+		}[fieldKind]
+		expr = value
+		switch {
+		case typ == "int32" || typ == "uint32" || typ == "uint64":
+			// narrowed like FillProto does, instead of wrapping around
+			if numberType != "int64" {
+				value = "int64(" + value + ")"
+			}
+			expr = g.ident(paginationPackage, "Clamp") + "[" + typ + "](" + value + ")"
+		case typ != numberType:
+			expr = typ + "(" + value + ")"
+		}
+		pointer = strings.ToUpper(typ[:1]) + typ[1:]
+	case kind == fieldKind && kind == protoreflect.BoolKind:
+		expr, pointer = value, "Bool"
+	case kind == fieldKind && kind == protoreflect.StringKind:
+		expr, pointer = value, "String"
+	default:
+		return "", fmt.Errorf("%s: a %s field can't hold %s", f.Desc.FullName(), fieldKind, kind)
+	}
+	switch {
+	case wrapper != "":
+		return g.ident(wrappersPackage, wrapper) + "(" + expr + ")", nil
+	case f.Desc.HasPresence():
+		return g.ident(protoPackage, pointer) + "(" + expr + ")", nil
+	}
+	return expr, nil
+}
+
+// fieldKind returns the kind of the scalar f or of the value of the
+// well-known wrapper f, with the name of its wrapperspb constructor.
+func fieldKind(f *protogen.Field) (kind protoreflect.Kind, wrapper string, err error) {
+	if f.Desc.IsList() || f.Desc.IsMap() || f.Oneof != nil && !f.Oneof.Desc.IsSynthetic() {
+		return 0, "", fmt.Errorf("%s: repeated, map and oneof fields can't hold pagination fields", f.Desc.FullName())
+	}
+	if f.Desc.Kind() != protoreflect.MessageKind {
+		return f.Desc.Kind(), "", nil
+	}
+	md := f.Desc.Message()
+	wrappers := map[protoreflect.FullName]string{
+		"google.protobuf.Int32Value": "Int32", "google.protobuf.Int64Value": "Int64",
+		"google.protobuf.UInt32Value": "UInt32", "google.protobuf.UInt64Value": "UInt64",
+		"google.protobuf.FloatValue": "Float", "google.protobuf.DoubleValue": "Double",
+		"google.protobuf.BoolValue": "Bool", "google.protobuf.StringValue": "String",
+	}
+	if wrapper, ok := wrappers[md.FullName()]; ok {
+		return md.Fields().ByName("value").Kind(), wrapper, nil
+	}
+	return 0, "", fmt.Errorf("%s: message %s can't hold pagination fields", f.Desc.FullName(), md.FullName())
+}
+
+func isNumber(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind,
+		protoreflect.FloatKind, protoreflect.DoubleKind:
+		return true
+	}
+	return false
+}
+
+// isRequest reports whether m has (pagination.field) options or page_num and
+// page_size fields, like ParseProto.
+func isRequest(m *protogen.Message) bool {
+	for _, f := range m.Fields {
+		if pageField(f) != pagination.PageField_PAGE_FIELD_UNSPECIFIED {
+			return true
+		}
+	}
+	return findField(m, "page_num", "num") != nil && findField(m, "page_size", "size") != nil
+}
+
+// isResponse reports whether m has total or has_next fields and page_num or
+// page_size fields, like FillProto.
+func isResponse(m *protogen.Message) bool {
+	return findField(m, "total", "has_next") != nil &&
+		(findField(m, "page_num", "num", "current_page") != nil || findField(m, "page_size", "size") != nil)
+}
+
+func pageField(f *protogen.Field) pagination.PageField {
+	field, _ := proto.GetExtension(f.Desc.Options(), pagination.E_Field).(pagination.PageField)
+	return field
+}
+
+// markedField returns the field of m with the (pagination.field) option field.
+func markedField(m *protogen.Message, field pagination.PageField) *protogen.Field {
+	for _, f := range m.Fields {
+		if pageField(f) == field {
+			return f
+		}
+	}
+	return nil
+}
+
+// sortField returns the repeated pagination.SortField field sort of m, nil
+// when there is none. Other fields called sort are left alone.
+func sortField(m *protogen.Message) *protogen.Field {
+	f := findField(m, "sort")
+	if f == nil || !f.Desc.IsList() || f.Message == nil ||
+		f.Message.Desc.FullName() != (*pagination.SortField)(nil).ProtoReflect().Descriptor().FullName() {
+		return nil
+	}
+	return f
+}
+
+// findField returns the first field of m called one of names, matched by
+// proto name or json_name.
+func findField(m *protogen.Message, names ...string) *protogen.Field {
+	for _, name := range names {
+		for _, f := range m.Fields {
+			if string(f.Desc.Name()) == name || f.Desc.JSONName() == name || f.Desc.JSONName() == lowerCamelCase(name) {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// lowerCamelCase converts a snake_case name, e.g. page_num to pageNum.
+func lowerCamelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
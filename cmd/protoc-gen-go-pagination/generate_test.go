@@ -0,0 +1,200 @@
+package main
+
+import (
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.github.com/uptutu/pagination"
+	"github.github.com/uptutu/pagination/cmd/protoc-gen-go-pagination/internal/examplepb"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// loadDescriptors reads a descriptor set written by protoc --include_imports
+// --descriptor_set_out.
+func loadDescriptors(t *testing.T, name string) *descriptorpb.FileDescriptorSet {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	set := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, proto.Unmarshal(data, set))
+	return set
+}
+
+func run(set *descriptorpb.FileDescriptorSet, files ...string) (*pluginpb.CodeGeneratorResponse, error) {
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: files,
+		Parameter:      proto.String("paths=source_relative"),
+		ProtoFile:      set.File,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			if err := generateFile(gen, f); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return gen.Response(), nil
+}
+
+func TestGenerate(t *testing.T) {
+	resp, err := run(loadDescriptors(t, "example.desc"), "example.proto")
+	require.NoError(t, err)
+	require.Nil(t, resp.Error)
+	require.Len(t, resp.File, 1)
+	assert.Equal(t, "example_pagination.pb.go", resp.File[0].GetName())
+
+	// the golden file is compiled, see TestGenerate_Example
+	golden := filepath.Join("internal", "examplepb", "example_pagination.pb.go")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, []byte(resp.File[0].GetContent()), 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), resp.File[0].GetContent())
+}
+
+func TestGenerate_NoPagination(t *testing.T) {
+	resp, err := run(loadDescriptors(t, "example.desc"), "google/protobuf/wrappers.proto")
+	require.NoError(t, err)
+	assert.Empty(t, resp.File)
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	set := loadDescriptors(t, "example.desc")
+	example := set.File[len(set.File)-1]
+	require.Equal(t, "example.proto", example.GetName())
+	example.MessageType = append(example.MessageType, &descriptorpb.DescriptorProto{
+		Name: proto.String("BadRequest"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{
+				Name:   proto.String("page_num"),
+				Number: proto.Int32(1),
+				Type:   descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum(),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			},
+			{
+				Name:   proto.String("page_size"),
+				Number: proto.Int32(2),
+				Type:   descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+			},
+		},
+	})
+	_, err := run(set, "example.proto")
+	assert.EqualError(t, err, "example.BadRequest.page_num: a bool field can't hold int64")
+}
+
+type exampleRequest interface {
+	proto.Message
+	ToPage(...pagination.Option) (pagination.Page, error)
+	ApplyPage(pagination.Page)
+}
+
+// TestGenerate_Example checks the adapters compiled from the golden file
+// against the reflection of ParseProto and FillProto.
+func TestGenerate_Example(t *testing.T) {
+	for _, req := range []exampleRequest{
+		&examplepb.ListUsersRequest{Limit: 500, Page: proto.Uint32(3), Sort: "name", Reverse: true, Filter: wrapperspb.String("bob")},
+		&examplepb.ListUsersRequest{},
+		&examplepb.SearchRequest{Pagination: &examplepb.SearchRequest_Paging{PageNum: 2, PageSize: wrapperspb.Int32(30), PageToken: "t"}},
+		&examplepb.SearchRequest{},
+		&examplepb.ListOrdersRequest{PageNum: "4", PageSize: wrapperspb.String(" 25 "), IsDescending: true},
+		&examplepb.ListOrdersRequest{},
+		&examplepb.ListOrdersRequest{Sort: []*pagination.SortField{
+			{Field: "created_at", Direction: pagination.SortField_DESC, Nulls: pagination.SortField_NULLS_LAST},
+			{Field: "id"},
+		}},
+		&examplepb.ListItemsRequest{PageNum: 2, PageSize: 5, OrderBy: "name", IsDescending: true},
+		&examplepb.ListItemsRequest{OrderBy: "name", Sort: []*pagination.SortField{{Field: "id", Direction: pagination.SortField_ASC}}},
+	} {
+		want, err := pagination.ParseProto(req)
+		require.NoError(t, err, req)
+		got, err := req.ToPage()
+		require.NoError(t, err, req)
+		assert.Equal(t, want, got, req)
+	}
+
+	for _, test := range []struct {
+		req exampleRequest
+		err error
+	}{
+		{req: &examplepb.ListUsersRequest{Sort: "password"}, err: pagination.ErrInvalidOrderBy},
+		{req: &examplepb.ListOrdersRequest{PageNum: "two"}, err: pagination.ErrInvalidPageNum},
+		{req: &examplepb.ListOrdersRequest{PageSize: wrapperspb.String("1e3")}, err: pagination.ErrInvalidPageSize},
+		{req: &examplepb.ListItemsRequest{Sort: []*pagination.SortField{{Field: "first name"}}}, err: pagination.ErrInvalidOrderBy},
+	} {
+		_, want := pagination.ParseProto(test.req)
+		_, got := test.req.ToPage()
+		assert.ErrorIs(t, got, test.err, test.req)
+		assert.EqualError(t, got, want.Error(), test.req)
+	}
+
+	// requests filled by ApplyPage parse back to the page
+	page := pagination.Page{Num: 3, Size: 40, OrderBy: "name", IsDescending: true, Query: "bob"}
+	for _, req := range []exampleRequest{
+		&examplepb.ListUsersRequest{},
+		&examplepb.SearchRequest{},
+		&examplepb.ListOrdersRequest{},
+		&examplepb.ListItemsRequest{Sort: []*pagination.SortField{{Field: "id"}}},
+	} {
+		req.ApplyPage(page)
+		want, err := pagination.ParseProto(req)
+		require.NoError(t, err, req)
+		got, err := req.ToPage()
+		require.NoError(t, err, req)
+		assert.Equal(t, want, got, req)
+		assert.Equal(t, []int{3, 40}, []int{got.Num, got.Size}, req)
+		if _, ok := req.(*examplepb.SearchRequest); ok {
+			continue // no order fields
+		}
+		keys, err := got.SortKeys()
+		require.NoError(t, err, req)
+		assert.Equal(t, []pagination.SortKey{{Field: "name", Descending: true}}, keys, req)
+	}
+
+	page = pagination.Page{Num: 2, Size: 10, NextToken: "next"}
+	page.SetTotal(35)
+	for _, resp := range []interface {
+		proto.Message
+		ApplyPage(pagination.Page)
+	}{
+		&examplepb.PageInfo{},
+		&examplepb.ListUsersResponse{},
+	} {
+		want := proto.Clone(resp)
+		require.NoError(t, page.FillProto(want))
+		resp.ApplyPage(page)
+		assert.True(t, proto.Equal(want, resp), "%v != %v", want, resp)
+	}
+
+	// numbers too large for a field are clamped like FillProto does
+	page = pagination.Page{Num: 1 << 40, Size: 1 << 40}
+	info := &examplepb.PageInfo{}
+	require.NoError(t, page.FillProto(info))
+	got := &examplepb.PageInfo{}
+	got.ApplyPage(page)
+	assert.True(t, proto.Equal(info, got), "%v != %v", info, got)
+	assert.Equal(t, int32(math.MaxInt32), got.GetPageNum())
+	assert.Equal(t, uint32(math.MaxUint32), got.GetPageSize())
+
+	users := &examplepb.ListUsersRequest{}
+	users.ApplyPage(pagination.Page{Num: 1 << 40, Size: 1 << 40})
+	assert.Equal(t, uint32(math.MaxUint32), users.GetPage())
+	assert.Equal(t, int32(math.MaxInt32), users.GetLimit())
+	users.ApplyPage(pagination.Page{Num: -1, Size: -1 << 40})
+	assert.Equal(t, uint32(0), users.GetPage())
+	assert.Equal(t, int32(math.MinInt32), users.GetLimit())
+}
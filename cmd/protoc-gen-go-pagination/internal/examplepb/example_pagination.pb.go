@@ -0,0 +1,150 @@
+// Code generated by protoc-gen-go-pagination. DO NOT EDIT.
+// source: example.proto
+
+package examplepb
+
+import (
+	pagination "github.github.com/uptutu/pagination"
+	proto "google.golang.org/protobuf/proto"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	strconv "strconv"
+)
+
+// Policy of ListUsersRequest, see its (pagination.policy) option.
+const (
+	ListUsersRequestDefaultSize = 20
+	ListUsersRequestMaxSize     = 100
+)
+
+// ListUsersRequestSortable lists the fields ListUsersRequest can be ordered by.
+var ListUsersRequestSortable = []string{"name", "created_at"}
+
+// ToPage returns the page requested by x.
+// The policy of ListUsersRequest is applied before options.
+func (x *ListUsersRequest) ToPage(options ...pagination.Option) (pagination.Page, error) {
+	return pagination.New(pagination.Page{
+		Num:          int(x.GetPage()),
+		Size:         int(x.GetLimit()),
+		OrderBy:      x.GetSort(),
+		IsDescending: x.GetReverse(),
+		Query:        x.GetFilter().GetValue(),
+	}, append([]pagination.Option{
+		pagination.WithDefaultSize(ListUsersRequestDefaultSize),
+		pagination.WithMaxSize(ListUsersRequestMaxSize),
+		pagination.WithSortable(ListUsersRequestSortable...),
+	}, options...)...)
+}
+
+// ApplyPage sets the pagination fields of x from p.
+func (x *ListUsersRequest) ApplyPage(p pagination.Page) {
+	x.Page = proto.Uint32(pagination.Clamp[uint32](int64(p.Num)))
+	x.Limit = pagination.Clamp[int32](int64(p.Size))
+	x.Sort = p.OrderBy
+	x.Reverse = p.IsDescending
+	x.Filter = wrapperspb.String(p.Query)
+}
+
+// ApplyPage fills x with the metadata of p, like FillResponse does.
+func (x *PageInfo) ApplyPage(p pagination.Page) {
+	m := p.Meta()
+	if m.Total != nil {
+		x.Total = wrapperspb.Int64(*m.Total)
+	}
+	x.PageNum = pagination.Clamp[int32](m.PageNum)
+	x.PageSize = pagination.Clamp[uint32](m.PageSize)
+	if m.LastPage != nil {
+		x.LastPage = proto.Int64(*m.LastPage)
+	}
+	x.HasNext = m.HasNext
+	x.NextPageToken = m.NextPageToken
+}
+
+// ApplyPage fills the page field of x with the metadata of p.
+func (x *ListUsersResponse) ApplyPage(p pagination.Page) {
+	if x.Page == nil {
+		x.Page = &PageInfo{}
+	}
+	x.Page.ApplyPage(p)
+}
+
+// ToPage returns the page requested by the pagination field of x.
+func (x *SearchRequest) ToPage(options ...pagination.Option) (pagination.Page, error) {
+	return x.GetPagination().ToPage(options...)
+}
+
+// ApplyPage sets the pagination field of x from p.
+func (x *SearchRequest) ApplyPage(p pagination.Page) {
+	if x.Pagination == nil {
+		x.Pagination = &SearchRequest_Paging{}
+	}
+	x.Pagination.ApplyPage(p)
+}
+
+// ToPage returns the page requested by x.
+func (x *SearchRequest_Paging) ToPage(options ...pagination.Option) (pagination.Page, error) {
+	return pagination.New(pagination.Page{
+		Num:   int(x.GetPageNum()),
+		Size:  int(x.GetPageSize().GetValue()),
+		Token: x.GetPageToken(),
+	}, options...)
+}
+
+// ApplyPage sets the pagination fields of x from p.
+func (x *SearchRequest_Paging) ApplyPage(p pagination.Page) {
+	x.PageNum = int64(p.Num)
+	x.PageSize = wrapperspb.Int32(pagination.Clamp[int32](int64(p.Size)))
+	x.PageToken = p.Token
+}
+
+// ToPage returns the page requested by x.
+func (x *ListOrdersRequest) ToPage(options ...pagination.Option) (pagination.Page, error) {
+	num, err := pagination.ParseInt(x.GetPageNum(), pagination.ErrInvalidPageNum)
+	if err != nil {
+		return pagination.Page{}, err
+	}
+	size, err := pagination.ParseInt(x.GetPageSize().GetValue(), pagination.ErrInvalidPageSize)
+	if err != nil {
+		return pagination.Page{}, err
+	}
+	orderBy, err := pagination.SortOrderBy(x.GetSort(), "")
+	if err != nil {
+		return pagination.Page{}, err
+	}
+	return pagination.New(pagination.Page{
+		Num:          num,
+		Size:         size,
+		OrderBy:      orderBy,
+		IsDescending: x.GetIsDescending(),
+	}, options...)
+}
+
+// ApplyPage sets the pagination fields of x from p.
+func (x *ListOrdersRequest) ApplyPage(p pagination.Page) {
+	x.PageNum = strconv.FormatInt(int64(p.Num), 10)
+	x.PageSize = wrapperspb.String(strconv.FormatInt(int64(p.Size), 10))
+	x.IsDescending = p.IsDescending
+	x.Sort = p.SortFields()
+}
+
+// ToPage returns the page requested by x.
+func (x *ListItemsRequest) ToPage(options ...pagination.Option) (pagination.Page, error) {
+	orderBy, err := pagination.SortOrderBy(x.GetSort(), x.GetOrderBy())
+	if err != nil {
+		return pagination.Page{}, err
+	}
+	return pagination.New(pagination.Page{
+		Num:          int(x.GetPageNum()),
+		Size:         int(x.GetPageSize()),
+		OrderBy:      orderBy,
+		IsDescending: x.GetIsDescending(),
+	}, options...)
+}
+
+// ApplyPage sets the pagination fields of x from p.
+func (x *ListItemsRequest) ApplyPage(p pagination.Page) {
+	x.PageNum = pagination.Clamp[int32](int64(p.Num))
+	x.PageSize = pagination.Clamp[int32](int64(p.Size))
+	x.OrderBy = p.OrderBy
+	x.IsDescending = p.IsDescending
+	x.Sort = nil
+}
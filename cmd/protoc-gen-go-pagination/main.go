@@ -0,0 +1,33 @@
+// Command protoc-gen-go-pagination is a protoc plugin generating typed
+// pagination adapters for protobuf messages, so services convert between
+// their messages and pagination.Page without reflection.
+//
+// For every request message, that is a message with (pagination.field)
+// options or with page_num and page_size fields, it generates ToPage and
+// ApplyPage methods and the constants of its (pagination.policy) option.
+// For every response message, a message with total or has_next fields besides
+// page_num or page_size, it generates ApplyPage. Messages holding such a
+// message in a page or pagination field delegate to it.
+//
+//	protoc --go_out=. --go-pagination_out=. --go-pagination_opt=paths=source_relative list.proto
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func main() {
+	protogen.Options{}.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, f := range gen.Files {
+			if !f.Generate {
+				continue
+			}
+			if err := generateFile(gen, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,154 @@
+package pagination
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"math"
+	"sync"
+	"time"
+)
+
+// Count is the result of a Counter.
+type Count struct {
+	Total int
+	// Capped is true when the real total is bigger than Total.
+	Capped bool
+	// Estimate is true when Total is not an exact count.
+	Estimate bool
+}
+
+// Counter counts the rows matched by a page request.
+type Counter interface {
+	Count(ctx context.Context, p Page) (Count, error)
+}
+
+type CounterFunc func(ctx context.Context, p Page) (Count, error)
+
+func (f CounterFunc) Count(ctx context.Context, p Page) (Count, error) {
+	return f(ctx, p)
+}
+
+// ExactCounter returns a Counter which trusts the given function to return the exact total.
+func ExactCounter(count func(ctx context.Context, p Page) (int, error)) Counter {
+	return CounterFunc(func(ctx context.Context, p Page) (Count, error) {
+		total, err := count(ctx, p)
+		if err != nil {
+			return Count{}, err
+		}
+		return Count{Total: total}, nil
+	})
+}
+
+// CappedCounter returns a Counter which stops counting after max rows, e.g. "10,000+".
+// The count function receives the limit it may stop at, which is max+1 so that
+// going over the cap can be detected, e.g. SELECT COUNT(*) FROM (... LIMIT ?).
+// max is lowered to math.MaxInt-1 so that the limit doesn't overflow.
+func CappedCounter(max int, count func(ctx context.Context, p Page, limit int) (int, error)) Counter {
+	if max > math.MaxInt-1 {
+		max = math.MaxInt - 1
+	}
+	return CounterFunc(func(ctx context.Context, p Page) (Count, error) {
+		total, err := count(ctx, p, max+1)
+		if err != nil {
+			return Count{}, err
+		}
+		if total > max {
+			return Count{Total: max, Capped: true}, nil
+		}
+		return Count{Total: total}, nil
+	})
+}
+
+// EstimatedCounter returns a Counter backed by an estimator, such as the row
+// estimate of a Postgres EXPLAIN.
+func EstimatedCounter(estimate func(ctx context.Context, p Page) (int, error)) Counter {
+	return CounterFunc(func(ctx context.Context, p Page) (Count, error) {
+		total, err := estimate(ctx, p)
+		if err != nil {
+			return Count{}, err
+		}
+		return Count{Total: total, Estimate: true}, nil
+	})
+}
+
+// Fingerprint identifies the rows a page request counts. Page number, size and
+// order don't change the total, so only the query and the filter are taken
+// into account.
+func Fingerprint(p Page) string {
+	key := p.Query
+	if p.Filter != "" {
+		key += "\x00" + p.Filter
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+var _now = time.Now
+
+type cachedCount struct {
+	count   Count
+	expires time.Time
+}
+
+type cachedCounter struct {
+	counter Counter
+	ttl     time.Duration
+	key     func(Page) string
+
+	mu      sync.Mutex
+	entries map[string]cachedCount
+}
+
+// CachedCounter caches the results of another Counter for ttl, keyed by
+// key(p). Fingerprint is used when key is nil.
+func CachedCounter(c Counter, ttl time.Duration, key func(Page) string) Counter {
+	if key == nil {
+		key = Fingerprint
+	}
+	return &cachedCounter{
+		counter: c,
+		ttl:     ttl,
+		key:     key,
+		entries: make(map[string]cachedCount),
+	}
+}
+
+func (c *cachedCounter) Count(ctx context.Context, p Page) (Count, error) {
+	key := c.key(p)
+	now := _now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.count, nil
+	}
+
+	count, err := c.counter.Count(ctx, p)
+	if err != nil {
+		return count, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedCount{count: count, expires: now.Add(c.ttl)}
+	return count, nil
+}
+
+// Count sets the total of the page with the given Counter.
+func (p *Page) Count(ctx context.Context, c Counter) error {
+	count, err := c.Count(ctx, *p)
+	if err != nil {
+		return err
+	}
+	p.SetTotal(count.Total)
+	p.TotalIsCapped = count.Capped
+	p.TotalIsEstimate = count.Estimate
+	return nil
+}
@@ -0,0 +1,120 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countResponse struct {
+	Total         int64
+	PageNum       int64
+	PageSize      int64
+	TotalCapped   bool
+	IsEstimate    bool
+	TotalIsCapped bool
+}
+
+func TestCounters(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		counter  Counter
+		excepted Count
+	}{
+		{
+			name: "exact",
+			counter: ExactCounter(func(ctx context.Context, p Page) (int, error) {
+				return 42, nil
+			}),
+			excepted: Count{Total: 42},
+		},
+		{
+			name: "capped under the cap",
+			counter: CappedCounter(100, func(ctx context.Context, p Page, limit int) (int, error) {
+				assert.Equal(t, 101, limit)
+				return 42, nil
+			}),
+			excepted: Count{Total: 42},
+		},
+		{
+			name: "capped over the cap",
+			counter: CappedCounter(100, func(ctx context.Context, p Page, limit int) (int, error) {
+				return limit, nil
+			}),
+			excepted: Count{Total: 100, Capped: true},
+		},
+		{
+			name: "capped at the largest int",
+			counter: CappedCounter(math.MaxInt, func(ctx context.Context, p Page, limit int) (int, error) {
+				assert.Equal(t, math.MaxInt, limit)
+				return limit, nil
+			}),
+			excepted: Count{Total: math.MaxInt - 1, Capped: true},
+		},
+		{
+			name: "estimated",
+			counter: EstimatedCounter(func(ctx context.Context, p Page) (int, error) {
+				return 1000, nil
+			}),
+			excepted: Count{Total: 1000, Estimate: true},
+		},
+	}
+	for _, test := range tests {
+		count, err := test.counter.Count(ctx, Page{})
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.excepted, count, test.name)
+	}
+
+	failed := errors.New("count failed")
+	_, err := ExactCounter(func(ctx context.Context, p Page) (int, error) {
+		return 0, failed
+	}).Count(ctx, Page{})
+	assert.Equal(t, failed, err)
+}
+
+func TestCachedCounter(t *testing.T) {
+	now := time.Unix(0, 0)
+	_now = func() time.Time { return now }
+	defer func() { _now = time.Now }()
+
+	calls := 0
+	counter := CachedCounter(ExactCounter(func(ctx context.Context, p Page) (int, error) {
+		calls++
+		return calls, nil
+	}), time.Minute, nil)
+
+	ctx := context.Background()
+	count, _ := counter.Count(ctx, Page{Num: 1, Query: "a"})
+	assert.Equal(t, 1, count.Total)
+	count, _ = counter.Count(ctx, Page{Num: 2, Query: "a"})
+	assert.Equal(t, 1, count.Total)
+	count, _ = counter.Count(ctx, Page{Num: 1, Query: "b"})
+	assert.Equal(t, 2, count.Total)
+	count, _ = counter.Count(ctx, Page{Num: 1, Query: "a", Filter: "active"})
+	assert.Equal(t, 3, count.Total)
+	assert.Equal(t, Fingerprint(Page{Query: "a"}), Fingerprint(Page{Num: 3, Query: "a", OrderBy: "id"}))
+
+	now = now.Add(time.Minute)
+	count, _ = counter.Count(ctx, Page{Num: 1, Query: "a"})
+	assert.Equal(t, 4, count.Total)
+}
+
+func TestPage_Count(t *testing.T) {
+	page, err := Parse(&testRequest{PageNum: 1, PageSize: 10}, WithProbe())
+	assert.NoError(t, err)
+	err = page.Count(context.Background(), CappedCounter(100, func(ctx context.Context, p Page, limit int) (int, error) {
+		return limit, nil
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, page.Total)
+	assert.True(t, page.TotalIsCapped)
+
+	resp := &countResponse{}
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, countResponse{Total: 100, PageNum: 1, PageSize: 10, TotalCapped: true, TotalIsCapped: true}, *resp)
+}
@@ -0,0 +1,247 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Query string keys, named after the fields of PaginationRequest.
+const (
+	ValuePageNum      = "page_num"
+	ValuePageSize     = "page_size"
+	ValueOrderBy      = "order_by"
+	ValueIsDescending = "is_descending"
+	ValueQuery        = "query"
+	ValuePageToken    = "page_token"
+	ValueFilter       = "filter"
+	ValueShowTotal    = "show_total"
+)
+
+// Values encodes the request part of the page as query string values,
+// zero values are left out.
+func (p Page) Values() url.Values {
+	v := url.Values{}
+	if p.Num != 0 {
+		v.Set(ValuePageNum, strconv.Itoa(p.Num))
+	}
+	if p.Size != 0 {
+		v.Set(ValuePageSize, strconv.Itoa(p.Size))
+	}
+	if p.OrderBy != "" {
+		v.Set(ValueOrderBy, p.OrderBy)
+	}
+	if p.IsDescending {
+		v.Set(ValueIsDescending, "true")
+	}
+	if p.Query != "" {
+		v.Set(ValueQuery, p.Query)
+	}
+	if p.Token != "" {
+		v.Set(ValuePageToken, p.Token)
+	}
+	if p.Filter != "" {
+		v.Set(ValueFilter, p.Filter)
+	}
+	if p.ShowTotal {
+		v.Set(ValueShowTotal, "true")
+	}
+	return v
+}
+
+// FromValues parses query string values encoded by Values.
+func FromValues(v url.Values, options ...Option) (Page, error) {
+	q := Page{
+		defaultSize: 15,
+	}
+	var err error
+	if s := v.Get(ValuePageNum); s != "" {
+		if q.Num, err = strconv.Atoi(s); err != nil {
+			return q, errors.Wrapf(ErrInvalidPageNum, "%q", s)
+		}
+	}
+	if s := v.Get(ValuePageSize); s != "" {
+		if q.Size, err = strconv.Atoi(s); err != nil {
+			return q, errors.Wrapf(ErrInvalidPageSize, "%q", s)
+		}
+	}
+	if s := v.Get(ValueIsDescending); s != "" {
+		if q.IsDescending, err = strconv.ParseBool(s); err != nil {
+			return q, errors.Wrapf(ErrInvalidIsDescending, "%q", s)
+		}
+	}
+	if s := v.Get(ValueShowTotal); s != "" {
+		if q.ShowTotal, err = strconv.ParseBool(s); err != nil {
+			return q, errors.Wrapf(ErrInvalidShowTotal, "%q", s)
+		}
+	}
+	q.OrderBy = v.Get(ValueOrderBy)
+	q.Query = v.Get(ValueQuery)
+	q.Token = v.Get(ValuePageToken)
+	q.Filter = v.Get(ValueFilter)
+
+	return q, q.apply(options)
+}
+
+type pageJSON struct {
+	Num             int    `json:"page_num,omitempty"`
+	Size            int    `json:"page_size,omitempty"`
+	OrderBy         string `json:"order_by,omitempty"`
+	IsDescending    bool   `json:"is_descending,omitempty"`
+	Query           string `json:"query,omitempty"`
+	Filter          string `json:"filter,omitempty"`
+	ShowTotal       bool   `json:"show_total,omitempty"`
+	Token           string `json:"page_token,omitempty"`
+	NextToken       string `json:"next_page_token,omitempty"`
+	Total           int    `json:"total,omitempty"`
+	TotalSet        bool   `json:"total_set,omitempty"`
+	TotalIsCapped   bool   `json:"total_is_capped,omitempty"`
+	TotalIsEstimate bool   `json:"total_is_estimate,omitempty"`
+	HasNext         *bool  `json:"has_next,omitempty"`
+	DefaultSize     *int   `json:"default_size,omitempty"`
+	Probe           bool   `json:"probe,omitempty"`
+	OverflowPolicy  string `json:"overflow_policy,omitempty"`
+	Allocate        *bool  `json:"allocate,omitempty"`
+	KeyNaming       string `json:"key_naming,omitempty"`
+}
+
+// Names of the options in the JSON encoding of a page, the defaults are left out.
+var (
+	_overflowPolicyNames = map[OverflowPolicy]string{OverflowError: "error"}
+	_keyNamingNames      = map[KeyNaming]string{CamelCase: "camel_case"}
+)
+
+// MarshalJSON encodes the whole state of the page, including the default
+// size and the options set by Parse: "probe" and "allocate" are booleans,
+// "overflow_policy" is "error" for OverflowError and "key_naming" is
+// "camel_case" for CamelCase, both are left out for the defaults.
+func (p Page) MarshalJSON() ([]byte, error) {
+	v := pageJSON{
+		Num:             p.Num,
+		Size:            p.Size,
+		OrderBy:         p.OrderBy,
+		IsDescending:    p.IsDescending,
+		Query:           p.Query,
+		Filter:          p.Filter,
+		ShowTotal:       p.ShowTotal,
+		Token:           p.Token,
+		NextToken:       p.NextToken,
+		Total:           p.Total,
+		TotalSet:        p.totalSet,
+		TotalIsCapped:   p.TotalIsCapped,
+		TotalIsEstimate: p.TotalIsEstimate,
+		DefaultSize:     &p.defaultSize,
+		Probe:           p.probe,
+	}
+	if p.hasNextSet {
+		v.HasNext = &p.hasNext
+	}
+	if p.allocateSet {
+		v.Allocate = &p.allocate
+	}
+	if p.overflow != OverflowClamp {
+		if v.OverflowPolicy = _overflowPolicyNames[p.overflow]; v.OverflowPolicy == "" {
+			return nil, errors.Errorf("unknown overflow policy %d", p.overflow)
+		}
+	}
+	if p.keyNaming != SnakeCase {
+		if v.KeyNaming = _keyNamingNames[p.keyNaming]; v.KeyNaming == "" {
+			return nil, errors.Errorf("unknown key naming %d", p.keyNaming)
+		}
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a page encoded by MarshalJSON. The default size is 15
+// when it is missing, and pages whose offset or limit overflow are rejected
+// like Parse does with OverflowError.
+func (p *Page) UnmarshalJSON(data []byte) error {
+	var v pageJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	q := Page{
+		Num:             v.Num,
+		Size:            v.Size,
+		OrderBy:         v.OrderBy,
+		IsDescending:    v.IsDescending,
+		Query:           v.Query,
+		Filter:          v.Filter,
+		ShowTotal:       v.ShowTotal,
+		Token:           v.Token,
+		NextToken:       v.NextToken,
+		Total:           v.Total,
+		TotalIsCapped:   v.TotalIsCapped,
+		TotalIsEstimate: v.TotalIsEstimate,
+		defaultSize:     15,
+		probe:           v.Probe,
+		totalSet:        v.TotalSet,
+	}
+	if v.DefaultSize != nil {
+		q.defaultSize = *v.DefaultSize
+	}
+	if v.HasNext != nil {
+		q.SetHasNext(*v.HasNext)
+	}
+	if v.Allocate != nil {
+		q.allocate, q.allocateSet = *v.Allocate, true
+	}
+	var ok bool
+	if v.OverflowPolicy != "" {
+		if q.overflow, ok = lookupName(_overflowPolicyNames, v.OverflowPolicy); !ok {
+			return errors.Errorf("unknown overflow policy %q", v.OverflowPolicy)
+		}
+	}
+	if v.KeyNaming != "" {
+		if q.keyNaming, ok = lookupName(_keyNamingNames, v.KeyNaming); !ok {
+			return errors.Errorf("unknown key naming %q", v.KeyNaming)
+		}
+	}
+	if err := q.checkOverflow(); err != nil {
+		return err
+	}
+	*p = q
+	return nil
+}
+
+// lookupName returns the key of name in names.
+func lookupName[K comparable](names map[K]string, name string) (K, bool) {
+	for k, n := range names {
+		if n == name {
+			return k, true
+		}
+	}
+	var zero K
+	return zero, false
+}
+
+func (p Page) ToProto() *PaginationRequest {
+	return &PaginationRequest{
+		PageNum:      int64(p.Num),
+		PageSize:     int64(p.Size),
+		OrderBy:      p.OrderBy,
+		IsDescending: p.IsDescending,
+		Query:        p.Query,
+		Filter:       p.Filter,
+		PageToken:    p.Token,
+		ShowTotal:    p.ShowTotal,
+	}
+}
+
+// FromProto parses a protobuf request, a nil request is an empty one.
+func FromProto(req *PaginationRequest, options ...Option) (Page, error) {
+	if req == nil {
+		req = &PaginationRequest{}
+	}
+	return Parse(req, options...)
+}
+
+// ToResponseProto returns the page as a protobuf response, filled the same
+// way as FillResponse does.
+func (p Page) ToResponseProto() *PaginationResponse {
+	resp := NewResult[struct{}](p, nil).Proto()
+	resp.TotalIsEstimate = p.TotalIsEstimate
+	return resp
+}
@@ -0,0 +1,141 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPage_Values(t *testing.T) {
+	p := Page{Num: 2, Size: 10, OrderBy: "name desc", IsDescending: true, Query: "a&b", Token: "t"}
+	assert.Equal(t, "is_descending=true&order_by=name+desc&page_num=2&page_size=10&page_token=t&query=a%26b", p.Values().Encode())
+	assert.Equal(t, "", Page{}.Values().Encode())
+
+	_, err := FromValues(url.Values{"page_num": {"two"}})
+	assert.ErrorIs(t, err, ErrInvalidPageNum)
+	_, err = FromValues(url.Values{"page_size": {"1.5"}})
+	assert.ErrorIs(t, err, ErrInvalidPageSize)
+	_, err = FromValues(url.Values{"is_descending": {"yes"}})
+	assert.ErrorIs(t, err, ErrInvalidIsDescending)
+
+	p = Page{Filter: "age > 18", ShowTotal: true}
+	assert.Equal(t, "filter=age+%3E+18&show_total=true", p.Values().Encode())
+	_, err = FromValues(url.Values{"show_total": {"maybe"}})
+	assert.ErrorIs(t, err, ErrInvalidShowTotal)
+
+	err = quick.Check(func(num, size int, orderBy string, descending bool, query, token, filter string, showTotal bool) bool {
+		p := Page{Num: num, Size: size, OrderBy: orderBy, IsDescending: descending, Query: query, Token: token,
+			Filter: filter, ShowTotal: showTotal, defaultSize: 20}
+		decoded, err := FromValues(p.Values(), WithDefaultSize(20))
+		return err == nil && assert.Equal(t, p, decoded)
+	}, nil)
+	assert.NoError(t, err)
+}
+
+func TestPage_JSON(t *testing.T) {
+	page, err := Parse(&testRequest{PageNum: 2}, WithProbe(), WithDefaultSize(20))
+	assert.NoError(t, err)
+	page.Trim(21)
+	data, err := json.Marshal(page)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"page_num":2,"has_next":true,"default_size":20,"probe":true}`, string(data))
+
+	var decoded Page
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, page, decoded)
+	assert.Equal(t, int32(21), decoded.Limit())
+
+	// a missing default size is the default of Parse
+	assert.NoError(t, json.Unmarshal([]byte(`{"page_num":2}`), &decoded))
+	assert.Equal(t, int32(15), decoded.Limit())
+	parsed, err := Parse(&testRequest{PageNum: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, parsed, decoded)
+
+	page, err = Parse(&testRequest{PageNum: 2, PageSize: 10}, WithOverflowPolicy(OverflowError), WithKeyNaming(CamelCase))
+	assert.NoError(t, err)
+	data, err = json.Marshal(page)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"page_num":2,"page_size":10,"default_size":15,"overflow_policy":"error","key_naming":"camel_case"}`, string(data))
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, page, decoded)
+
+	assert.Equal(t, ErrOffsetOverflow, json.Unmarshal([]byte(`{"page_num":3,"page_size":2147483647,"overflow_policy":"error"}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"overflow_policy":"1"}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"key_naming":"kebab"}`), &decoded))
+
+	err = quick.Check(func(num, size int, orderBy string, descending bool, query, token, nextToken string,
+		total int, flags uint8, defaultSize int, naming bool, filter string, showTotal bool) bool {
+		p := Page{
+			Num:             num,
+			Size:            size,
+			OrderBy:         orderBy,
+			IsDescending:    descending,
+			Query:           query,
+			Filter:          filter,
+			ShowTotal:       showTotal,
+			Token:           token,
+			NextToken:       nextToken,
+			Total:           total,
+			TotalIsCapped:   flags&1 != 0,
+			TotalIsEstimate: flags&2 != 0,
+			defaultSize:     defaultSize,
+			probe:           flags&4 != 0,
+			totalSet:        flags&8 != 0,
+			overflow:        OverflowPolicy(flags >> 7),
+		}
+		if flags&16 != 0 {
+			p.SetHasNext(flags&32 != 0)
+		}
+		if naming {
+			p.keyNaming = CamelCase
+		}
+		if flags&64 != 0 {
+			p.allocate, p.allocateSet = flags&32 != 0, true
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			return false
+		}
+		var decoded Page
+		if err := p.checkOverflow(); err != nil {
+			return json.Unmarshal(data, &decoded) == err
+		}
+		return json.Unmarshal(data, &decoded) == nil && assert.Equal(t, p, decoded)
+	}, nil)
+	assert.NoError(t, err)
+}
+
+func TestPage_Proto(t *testing.T) {
+	page, err := FromProto(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Page{defaultSize: 15}, page)
+
+	err = quick.Check(func(num, size int32, orderBy string, descending bool, query, filter, token string, showTotal bool) bool {
+		p := Page{Num: int(num), Size: int(size), OrderBy: orderBy, IsDescending: descending, Query: query,
+			Filter: filter, Token: token, ShowTotal: showTotal, defaultSize: 15}
+		decoded, err := FromProto(p.ToProto())
+		return err == nil && assert.Equal(t, p, decoded)
+	}, nil)
+	assert.NoError(t, err)
+
+	page = Page{Num: 2, Size: 10}
+	page.SetTotal(25)
+	resp := &PaginationResponse{}
+	assert.NoError(t, page.FillResponse(resp))
+	assert.True(t, proto.Equal(resp, page.ToResponseProto()))
+	assert.Equal(t, int64(3), resp.LastPage)
+
+	page.NextToken, page.TotalIsEstimate = "next", true
+	resp = &PaginationResponse{}
+	assert.NoError(t, page.FillResponse(resp))
+	assert.True(t, proto.Equal(&PaginationResponse{
+		Total: 25, PageNum: 2, LastPage: 3, PageSize: 10,
+		NextPageToken: "next", HasNext: true, TotalPages: 3, TotalIsEstimate: true,
+	}, resp))
+	assert.True(t, proto.Equal(resp, page.ToResponseProto()))
+}
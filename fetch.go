@@ -0,0 +1,165 @@
+package pagination
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy decides how often and when a failed page is fetched again.
+type RetryPolicy struct {
+	// Attempts is the number of attempts per page, including the first one.
+	Attempts int
+	// Backoff returns the delay before the given retry, starting at 1.
+	Backoff func(retry int) time.Duration
+	// Retryable reports whether an error is worth retrying, all errors are when nil.
+	Retryable func(err error) bool
+}
+
+// ExponentialBackoff doubles the delay on every retry, starting at base and capped at max.
+func ExponentialBackoff(base, max time.Duration) func(retry int) time.Duration {
+	return func(retry int) time.Duration {
+		d := base
+		for i := 1; i < retry && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			return max
+		}
+		return d
+	}
+}
+
+type fetchAllConfig struct {
+	concurrency int
+	retry       RetryPolicy
+}
+
+type FetchAllOption func(*fetchAllConfig)
+
+// WithConcurrency limits the number of pages fetched at the same time, 4 by default.
+func WithConcurrency(n int) FetchAllOption {
+	return func(c *fetchAllConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithRetry retries failed pages following the policy, pages are fetched once by default.
+func WithRetry(policy RetryPolicy) FetchAllOption {
+	return func(c *fetchAllConfig) {
+		c.retry = policy
+	}
+}
+
+// FetchAll fetches the first page to learn the total, then fetches the
+// remaining pages in parallel, with the page size the first page was served
+// with. The items are returned in page order together with the first page.
+// The first error cancels the pages still being fetched.
+func FetchAll[T any](ctx context.Context, fetch FetchFunc[T], p Page, options ...FetchAllOption) ([]T, Page, error) {
+	c := fetchAllConfig{concurrency: 4}
+	for i := range options {
+		options[i](&c)
+	}
+	if c.concurrency < 1 {
+		c.concurrency = 1
+	}
+	if p.Num < 1 {
+		p.Num = 1
+	}
+
+	items, first, err := fetchWithRetry(ctx, fetch, p, c.retry)
+	if err != nil {
+		return nil, first, err
+	}
+
+	size := first.Size
+	if size == 0 {
+		size = int(p.limit())
+	}
+	if seen, ok := mulInt64(int64(p.Num), int64(size)); size == 0 || !ok || int64(first.Total) <= seen {
+		return items, first, nil
+	}
+	lastPage := Page{Size: size, Total: first.Total}.LastPage()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// pages are kept by number, the total comes from the server and may be
+	// too large to allocate up front
+	pages := make(map[int][]T)
+	nums := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		once     sync.Once
+		firstErr error
+	)
+	for i := 0; i < c.concurrency && i < lastPage-p.Num; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for num := range nums {
+				req := p
+				req.Num, req.Size = num, size
+				got, _, err := fetchWithRetry(ctx, fetch, req, c.retry)
+				if err != nil {
+					once.Do(func() {
+						firstErr = errors.Wrapf(err, "page %d", num)
+						cancel()
+					})
+					continue
+				}
+				mu.Lock()
+				pages[num] = got
+				mu.Unlock()
+			}
+		}()
+	}
+
+send:
+	for num := p.Num + 1; num <= lastPage; num++ {
+		select {
+		case nums <- num:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(nums)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, first, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, first, err
+	}
+	for num := p.Num + 1; num <= lastPage; num++ {
+		items = append(items, pages[num]...)
+	}
+	return items, first, nil
+}
+
+func fetchWithRetry[T any](ctx context.Context, fetch FetchFunc[T], p Page, policy RetryPolicy) ([]T, Page, error) {
+	for retry := 0; ; retry++ {
+		items, got, err := fetch(ctx, p)
+		if err == nil {
+			return items, got, nil
+		}
+		if retry+1 >= policy.Attempts || ctx.Err() != nil ||
+			(policy.Retryable != nil && !policy.Retryable(err)) {
+			return nil, got, err
+		}
+
+		if policy.Backoff != nil {
+			timer := time.NewTimer(policy.Backoff(retry + 1))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, got, err
+			}
+		}
+	}
+}
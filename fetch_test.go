@@ -0,0 +1,140 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchAll(t *testing.T) {
+	api := &fakeAPI{total: 95, withTotal: true}
+	var mu sync.Mutex
+	var running, maxRunning int32
+	fetch := func(ctx context.Context, p Page) ([]int, Page, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		mu.Lock()
+		if n > maxRunning {
+			maxRunning = n
+		}
+		mu.Unlock()
+		// later pages return first
+		time.Sleep(time.Duration(20-p.Num) * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		return api.byNumber(ctx, p)
+	}
+
+	items, page, err := FetchAll(context.Background(), fetch, Page{Num: 1, Size: 10}, WithConcurrency(3))
+	assert.NoError(t, err)
+	assert.Equal(t, api.items(), items)
+	assert.Equal(t, 95, page.Total)
+	assert.Len(t, api.requests, 10)
+	assert.LessOrEqual(t, maxRunning, int32(3))
+
+	// a single page
+	items, _, err = FetchAll(context.Background(), (&fakeAPI{total: 5, withTotal: true}).byNumber, Page{Num: 1, Size: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, items)
+}
+
+func TestFetchAll_ClampedSize(t *testing.T) {
+	api := &fakeAPI{total: 95, withTotal: true}
+	var mu sync.Mutex
+	var sizes []int
+	fetch := func(ctx context.Context, p Page) ([]int, Page, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, p.Size)
+		if p.Size > 20 {
+			p.Size = 20
+		}
+		return api.byNumber(ctx, p)
+	}
+
+	items, page, err := FetchAll(context.Background(), fetch, Page{Num: 2, Size: 50})
+	assert.NoError(t, err)
+	assert.Equal(t, api.items()[20:], items)
+	assert.Equal(t, 20, page.Size)
+	assert.Equal(t, []int{50, 20, 20, 20}, sizes)
+}
+
+func TestFetchAll_Retry(t *testing.T) {
+	api := &fakeAPI{total: 30, withTotal: true}
+	failed := errors.New("failed")
+	var mu sync.Mutex
+	attempts := map[int]int{}
+	fetch := func(ctx context.Context, p Page) ([]int, Page, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[p.Num]++
+		if p.Num == 2 && attempts[p.Num] < 3 {
+			return nil, p, failed
+		}
+		return api.byNumber(ctx, p)
+	}
+
+	items, _, err := FetchAll(context.Background(), fetch, Page{Num: 1, Size: 10},
+		WithRetry(RetryPolicy{Attempts: 3, Backoff: ExponentialBackoff(time.Millisecond, 5*time.Millisecond)}))
+	assert.NoError(t, err)
+	assert.Equal(t, api.items(), items)
+	assert.Equal(t, 3, attempts[2])
+
+	attempts = map[int]int{}
+	_, _, err = FetchAll(context.Background(), fetch, Page{Num: 1, Size: 10},
+		WithRetry(RetryPolicy{Attempts: 3, Retryable: func(err error) bool { return false }}))
+	assert.ErrorIs(t, err, failed)
+	assert.Equal(t, 1, attempts[2])
+}
+
+func TestFetchAll_Abort(t *testing.T) {
+	failed := errors.New("failed")
+	var fetched int32
+	fetch := func(ctx context.Context, p Page) ([]int, Page, error) {
+		atomic.AddInt32(&fetched, 1)
+		if p.Num == 2 {
+			return nil, p, failed
+		}
+		if p.Num > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, p, ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+		return []int{p.Num}, Page{Num: p.Num, Size: 1, Total: 1000}, nil
+	}
+
+	start := time.Now()
+	_, _, err := FetchAll(context.Background(), fetch, Page{Num: 1, Size: 1}, WithConcurrency(2))
+	assert.ErrorIs(t, err, failed)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Less(t, atomic.LoadInt32(&fetched), int32(10))
+}
+
+func TestFetchAll_HugeTotal(t *testing.T) {
+	failed := errors.New("failed")
+	fetch := func(ctx context.Context, p Page) ([]int, Page, error) {
+		if p.Num == 3 {
+			return nil, p, failed
+		}
+		return []int{p.Num}, Page{Num: p.Num, Size: 1, Total: 1 << 62}, nil
+	}
+
+	_, _, err := FetchAll(context.Background(), fetch, Page{Num: 1, Size: 1}, WithConcurrency(1))
+	assert.ErrorIs(t, err, failed)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, backoff(1))
+	assert.Equal(t, 20*time.Millisecond, backoff(2))
+	assert.Equal(t, 40*time.Millisecond, backoff(3))
+	assert.Equal(t, 50*time.Millisecond, backoff(4))
+}
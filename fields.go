@@ -0,0 +1,60 @@
+package pagination
+
+import "reflect"
+
+// fieldNames returns the names of the exported fields of the struct type t,
+// including the ones promoted from embedded structs, in declaration order.
+// Like Go selectors, a shallower field shadows deeper ones with the same name
+// and names which are ambiguous at the same depth are left out.
+func fieldNames(t reflect.Type) []string {
+	var names []string
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() {
+			continue
+		}
+		if visible, ok := t.FieldByName(f.Name); ok && equalIndex(visible.Index, f.Index) {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+func hasField(t reflect.Type, name string) bool {
+	f, ok := t.FieldByName(name)
+	return ok && f.IsExported()
+}
+
+// field returns the field called name of the struct v, following embedded
+// structs. Nil embedded pointers on the way are allocated when alloc is set,
+// otherwise, or when they can't be set, the field is reported missing.
+func field(v reflect.Value, name string, alloc bool) (reflect.Value, bool) {
+	sf, ok := v.Type().FieldByName(name)
+	if !ok || !sf.IsExported() {
+		return reflect.Value{}, false
+	}
+	for i, x := range sf.Index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func equalIndex(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
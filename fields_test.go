@@ -0,0 +1,133 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Paging struct {
+	PageNum  int
+	PageSize int
+	OrderBy  string
+}
+
+type PagingResult struct {
+	Total    int64
+	PageNum  int64
+	PageSize int64
+	HasNext  bool
+}
+
+type Sorting struct {
+	OrderBy string
+}
+
+type paging struct {
+	PageNum  int
+	PageSize int
+}
+
+func TestParse_Embedded(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     interface{}
+		excepted Page
+	}{
+		{
+			name: "embedded struct",
+			data: struct {
+				Paging
+				Query string
+			}{Paging: Paging{PageNum: 2, PageSize: 10, OrderBy: "id"}, Query: "q"},
+			excepted: Page{Num: 2, Size: 10, OrderBy: "id", Query: "q", defaultSize: 15},
+		},
+		{
+			name:     "embedded pointer",
+			data:     struct{ *Paging }{&Paging{PageNum: 2, PageSize: 10}},
+			excepted: Page{Num: 2, Size: 10, defaultSize: 15},
+		},
+		{
+			name:     "nil embedded pointer",
+			data:     struct{ *Paging }{},
+			excepted: Page{defaultSize: 15},
+		},
+		{
+			name:     "unexported embedded struct",
+			data:     struct{ paging }{paging{PageNum: 2, PageSize: 10}},
+			excepted: Page{Num: 2, Size: 10, defaultSize: 15},
+		},
+		{
+			name: "outer field shadows embedded",
+			data: struct {
+				Paging
+				PageSize int
+			}{Paging: Paging{PageNum: 2, PageSize: 10}, PageSize: 20},
+			excepted: Page{Num: 2, Size: 20, defaultSize: 15},
+		},
+		{
+			name: "ambiguous fields are ignored",
+			data: struct {
+				Paging
+				Sorting
+			}{Paging: Paging{PageNum: 2, PageSize: 10, OrderBy: "id"}, Sorting: Sorting{OrderBy: "name"}},
+			excepted: Page{Num: 2, Size: 10, defaultSize: 15},
+		},
+		{
+			name: "embedded in nested request",
+			data: struct {
+				Page *struct{ Paging }
+			}{Page: &struct{ Paging }{Paging{PageNum: 2, PageSize: 10}}},
+			excepted: Page{Num: 2, Size: 10, defaultSize: 15},
+		},
+	}
+	for _, test := range tests {
+		page, err := Parse(test.data)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.excepted, page, test.name)
+	}
+}
+
+func TestPage_FillEmbeddedResponse(t *testing.T) {
+	page, err := Parse(&testRequest{PageNum: 2, PageSize: 10})
+	assert.NoError(t, err)
+	page.SetTotal(25)
+
+	resp := &struct {
+		PagingResult
+		Data []string
+	}{}
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, PagingResult{Total: 25, PageNum: 2, PageSize: 10, HasNext: true}, resp.PagingResult)
+
+	// nil embedded pointers are allocated
+	ptrResp := &struct{ *PagingResult }{}
+	assert.NoError(t, page.FillResponse(ptrResp))
+	assert.Equal(t, &PagingResult{Total: 25, PageNum: 2, PageSize: 10, HasNext: true}, ptrResp.PagingResult)
+
+	// the outer field shadows the embedded one
+	shadowResp := &struct {
+		*PagingResult
+		Total string
+	}{}
+	assert.NoError(t, page.FillResponse(shadowResp))
+	assert.Equal(t, "25", shadowResp.Total)
+	assert.Equal(t, int64(0), shadowResp.PagingResult.Total)
+	assert.Equal(t, int64(10), shadowResp.PageSize)
+
+	// embedded struct inside the Page container
+	nested := &struct {
+		Page struct{ *PagingResult }
+	}{}
+	assert.NoError(t, page.FillResponse(nested))
+	assert.Equal(t, int64(25), nested.Page.Total)
+
+	// embedded structs without paging fields are left alone
+	type Meta struct{ Version string }
+	metaResp := &struct {
+		*Meta
+		PagingResult
+	}{}
+	assert.NoError(t, page.FillResponse(metaResp))
+	assert.Nil(t, metaResp.Meta)
+}
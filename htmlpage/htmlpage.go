@@ -0,0 +1,161 @@
+// Package htmlpage builds the page links of a server-rendered pagination
+// control, e.g. « 1 … 4 5 [6] 7 8 … 20 ».
+package htmlpage
+
+import (
+	"html/template"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.github.com/uptutu/pagination"
+)
+
+// Link is one element of the control. Gap links stand for the ellipsis
+// between page ranges and have no number or URL.
+type Link struct {
+	Number   int
+	URL      string
+	Active   bool
+	Disabled bool
+	Gap      bool
+}
+
+type View struct {
+	Prev     Link
+	Next     Link
+	Links    []Link
+	Num      int
+	LastPage int
+	Total    int
+}
+
+type config struct {
+	window int
+	edges  int
+}
+
+type Option func(*config)
+
+// WithWindow sets the number of pages shown on each side of the current page, 2 by default.
+func WithWindow(n int) Option {
+	return func(c *config) {
+		c.window = n
+	}
+}
+
+// WithEdges sets the number of pages always shown at the start and the end, 1 by default.
+func WithEdges(n int) Option {
+	return func(c *config) {
+		c.edges = n
+	}
+}
+
+// New builds the view of a page with Total set. pageURL returns the URL of a
+// page number, see QueryURL.
+func New(p pagination.Page, pageURL func(num int) string, options ...Option) View {
+	c := config{window: 2, edges: 1}
+	for i := range options {
+		options[i](&c)
+	}
+
+	last := p.LastPage()
+	num := p.Num
+	if num < 1 {
+		num = 1
+	}
+	if last > 0 && num > last {
+		num = last
+	}
+	v := View{Num: num, LastPage: last, Total: p.Total}
+
+	link := func(n int) Link {
+		return Link{Number: n, URL: pageURL(n), Active: n == num}
+	}
+	v.Prev = Link{Number: num - 1, Disabled: num <= 1}
+	if !v.Prev.Disabled {
+		v.Prev.URL = pageURL(num - 1)
+	}
+	v.Next = Link{Number: num + 1, Disabled: num >= last}
+	if !v.Next.Disabled {
+		v.Next.URL = pageURL(num + 1)
+	}
+
+	shown := make(map[int]bool)
+	add := func(from, to int) {
+		for n := from; n <= to; n++ {
+			if n >= 1 && n <= last {
+				shown[n] = true
+			}
+		}
+	}
+	add(1, c.edges)
+	add(num-c.window, num+c.window)
+	add(last-c.edges+1, last)
+
+	numbers := make([]int, 0, len(shown))
+	for n := range shown {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	prev := 0
+	for _, n := range numbers {
+		switch {
+		case n-prev == 2:
+			// a gap of one page shows the page instead
+			v.Links = append(v.Links, link(n-1))
+		case n-prev > 2:
+			v.Links = append(v.Links, Link{Gap: true, Disabled: true})
+		}
+		v.Links = append(v.Links, link(n))
+		prev = n
+	}
+	return v
+}
+
+// QueryURL returns a pageURL function setting the page number as the given
+// query parameter of base, keeping the other parameters.
+func QueryURL(base *url.URL, param string) func(num int) string {
+	return func(num int) string {
+		u := *base
+		q := u.Query()
+		q.Set(param, strconv.Itoa(num))
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+}
+
+// FuncMap returns the template functions of the package:
+//
+//	{{template "pagination" (paginator .Page .URL)}}
+//
+// paginator builds a View from a page and the current URL, with the page
+// number in the "page" query parameter.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"paginator": func(p pagination.Page, current *url.URL) View {
+			return New(p, QueryURL(current, "page"))
+		},
+	}
+}
+
+// DefaultTemplate defines the "pagination" template rendering a View.
+const DefaultTemplate = `{{define "pagination"}}<nav class="pagination"><ul>
+{{- with .Prev}}<li class="prev{{if .Disabled}} disabled{{end}}">{{if .Disabled}}<span>&laquo;</span>{{else}}<a href="{{.URL}}" rel="prev">&laquo;</a>{{end}}</li>{{end}}
+{{- range .Links}}
+{{- if .Gap}}<li class="gap"><span>&hellip;</span></li>
+{{- else if .Active}}<li class="active"><span aria-current="page">{{.Number}}</span></li>
+{{- else}}<li><a href="{{.URL}}">{{.Number}}</a></li>
+{{- end}}
+{{- end}}
+{{- with .Next}}<li class="next{{if .Disabled}} disabled{{end}}">{{if .Disabled}}<span>&raquo;</span>{{else}}<a href="{{.URL}}" rel="next">&raquo;</a>{{end}}</li>{{end -}}
+</ul></nav>{{end}}`
+
+var _template = template.Must(template.New("htmlpage").Funcs(FuncMap()).Parse(DefaultTemplate))
+
+// Render writes the view with the default template.
+func Render(w io.Writer, v View) error {
+	return _template.ExecuteTemplate(w, "pagination", v)
+}
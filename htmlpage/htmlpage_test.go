@@ -0,0 +1,93 @@
+package htmlpage
+
+import (
+	"html/template"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.github.com/uptutu/pagination"
+)
+
+// control renders a view as text, e.g. « 1 … 4 5 [6] 7 8 … 20 ».
+func control(v View) string {
+	parts := []string{"«"}
+	if v.Prev.Disabled {
+		parts[0] = "(«)"
+	}
+	for _, l := range v.Links {
+		switch {
+		case l.Gap:
+			parts = append(parts, "…")
+		case l.Active:
+			parts = append(parts, "["+strconv.Itoa(l.Number)+"]")
+		default:
+			parts = append(parts, strconv.Itoa(l.Number))
+		}
+	}
+	if v.Next.Disabled {
+		return strings.Join(append(parts, "(»)"), " ")
+	}
+	return strings.Join(append(parts, "»"), " ")
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		num      int
+		total    int
+		options  []Option
+		excepted string
+	}{
+		{name: "middle", num: 6, total: 200, excepted: "« 1 … 4 5 [6] 7 8 … 20 »"},
+		{name: "first", num: 1, total: 200, excepted: "(«) [1] 2 3 … 20 »"},
+		{name: "last", num: 20, total: 200, excepted: "« 1 … 18 19 [20] (»)"},
+		{name: "gap of one page is shown", num: 5, total: 200, excepted: "« 1 2 3 4 [5] 6 7 … 20 »"},
+		{name: "few pages", num: 2, total: 30, excepted: "« 1 [2] 3 »"},
+		{name: "single page", num: 1, total: 5, excepted: "(«) [1] (»)"},
+		{name: "no items", num: 1, total: 0, excepted: "(«) (»)"},
+		{name: "beyond the end", num: 30, total: 200, excepted: "« 1 … 18 19 [20] (»)"},
+		{
+			name:     "window and edges",
+			num:      10,
+			total:    200,
+			options:  []Option{WithWindow(1), WithEdges(2)},
+			excepted: "« 1 2 … 9 [10] 11 … 19 20 »",
+		},
+	}
+	for _, test := range tests {
+		p := pagination.Page{Num: test.num, Size: 10, Total: test.total}
+		v := New(p, func(n int) string { return "/items?page=" + strconv.Itoa(n) }, test.options...)
+		assert.Equal(t, test.excepted, control(v), test.name)
+	}
+
+	v := New(pagination.Page{Num: 2, Size: 10, Total: 30}, func(n int) string { return "/p/" + strconv.Itoa(n) })
+	assert.Equal(t, Link{Number: 1, URL: "/p/1"}, v.Prev)
+	assert.Equal(t, Link{Number: 2, URL: "/p/2", Active: true}, v.Links[1])
+	assert.Equal(t, 3, v.LastPage)
+}
+
+func TestRender(t *testing.T) {
+	current, err := url.Parse("/items?q=a%26b&page=2")
+	require.NoError(t, err)
+	assert.Equal(t, "/items?page=3&q=a%26b", QueryURL(current, "page")(3))
+
+	tmpl := template.Must(template.New("page").Funcs(FuncMap()).Parse(DefaultTemplate + `{{template "pagination" (paginator .Page .URL)}}`))
+	var b strings.Builder
+	require.NoError(t, tmpl.Execute(&b, map[string]interface{}{
+		"Page": pagination.Page{Num: 2, Size: 10, Total: 30},
+		"URL":  current,
+	}))
+	html := b.String()
+	assert.Contains(t, html, `<li class="prev"><a href="/items?page=1&amp;q=a%26b" rel="prev">&laquo;</a></li>`)
+	assert.Contains(t, html, `<li class="active"><span aria-current="page">2</span></li>`)
+	assert.Contains(t, html, `<li class="next"><a href="/items?page=3&amp;q=a%26b" rel="next">&raquo;</a></li>`)
+
+	var direct strings.Builder
+	require.NoError(t, Render(&direct, New(pagination.Page{Num: 2, Size: 10, Total: 30}, QueryURL(current, "page"))))
+	assert.Equal(t, html, direct.String())
+}
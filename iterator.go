@@ -0,0 +1,153 @@
+package pagination
+
+import (
+	"context"
+	"iter"
+)
+
+// FetchFunc fetches one page of a paginated API. The returned Page describes
+// the response: Total, NextToken or HasNext (see SetHasNext) tell whether there
+// are more pages.
+type FetchFunc[T any] func(ctx context.Context, p Page) ([]T, Page, error)
+
+type iteratorConfig struct {
+	tokens bool
+}
+
+type IteratorOption func(*iteratorConfig)
+
+// WithTokens iterates by page token only: the iteration stops at the first
+// response without NextToken. Without this option token mode starts with the
+// first response carrying a NextToken.
+func WithTokens() IteratorOption {
+	return func(c *iteratorConfig) {
+		c.tokens = true
+	}
+}
+
+// Iterator walks a paginated API page after page.
+//
+//	it := pagination.NewIterator(ctx, fetch, pagination.Page{Num: 1, Size: 100})
+//	for it.Next() {
+//		use(it.Value())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx    context.Context
+	fetch  FetchFunc[T]
+	config iteratorConfig
+
+	request Page
+	page    Page
+	items   []T
+	index   int
+	value   T
+	err     error
+	done    bool
+}
+
+// NewIterator returns an Iterator starting at the page p.
+func NewIterator[T any](ctx context.Context, fetch FetchFunc[T], p Page, options ...IteratorOption) *Iterator[T] {
+	it := &Iterator[T]{ctx: ctx, fetch: fetch, request: p}
+	for i := range options {
+		options[i](&it.config)
+	}
+	return it
+}
+
+// Next advances to the next item, fetching the next page when needed. It
+// returns false at the end of the iteration or on error.
+func (it *Iterator[T]) Next() bool {
+	for it.index >= len(it.items) {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.err = it.ctx.Err(); it.err != nil {
+			return false
+		}
+
+		items, page, err := it.fetch(it.ctx, it.request)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		var more bool
+		it.items, it.index, it.page = items, 0, page
+		it.request, more = it.config.following(it.request, page, len(items))
+		it.done = !more
+	}
+
+	it.value = it.items[it.index]
+	it.index++
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Page returns the last fetched page.
+func (it *Iterator[T]) Page() Page {
+	return it.page
+}
+
+// All returns the remaining items as a sequence for range loops. An error is
+// yielded as the last pair.
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.Value(), nil) {
+				return
+			}
+		}
+		if it.err != nil {
+			var zero T
+			yield(zero, it.err)
+		}
+	}
+}
+
+// following returns the request of the page after req, whose response was
+// got with n items. ok is false when req was the last page.
+func (c *iteratorConfig) following(req, got Page, n int) (next Page, ok bool) {
+	next = req
+	next.Num = req.Num + 1
+	if req.Num <= 0 {
+		next.Num = 2
+	}
+
+	if got.NextToken != "" {
+		c.tokens = true
+		next.Token = got.NextToken
+		return next, true
+	}
+	if c.tokens || n == 0 {
+		return next, false
+	}
+	if got.hasNextSet {
+		return next, got.hasNext
+	}
+
+	size := got.Size
+	if size == 0 {
+		size = int(req.limit())
+	}
+	if got.Total > 0 && size > 0 {
+		num := req.Num
+		if num <= 0 {
+			num = 1
+		}
+		seen, ok := mulInt64(int64(num), int64(size))
+		return next, ok && seen < int64(got.Total)
+	}
+	// without a total a short page is the last one
+	return next, size > 0 && n >= size
+}
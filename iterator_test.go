@@ -0,0 +1,132 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPI serves the numbers 1..total.
+type fakeAPI struct {
+	total     int
+	withTotal bool
+	requests  []Page
+}
+
+func (a *fakeAPI) byNumber(ctx context.Context, p Page) ([]int, Page, error) {
+	a.requests = append(a.requests, p)
+	items, page := PaginateSlice(a.items(), p)
+	if !a.withTotal {
+		page.Total = 0
+	}
+	return items, page, nil
+}
+
+func (a *fakeAPI) byToken(ctx context.Context, p Page) ([]int, Page, error) {
+	a.requests = append(a.requests, p)
+	start := 0
+	if p.Token != "" {
+		start, _ = strconv.Atoi(p.Token)
+	}
+	items := a.items()[start:]
+	if len(items) > p.Size {
+		items = items[:p.Size]
+	}
+	page := Page{Size: p.Size}
+	if start+len(items) < a.total {
+		page.NextToken = strconv.Itoa(start + len(items))
+	}
+	return items, page, nil
+}
+
+func (a *fakeAPI) items() []int {
+	items := make([]int, a.total)
+	for i := range items {
+		items[i] = i + 1
+	}
+	return items
+}
+
+func TestIterator(t *testing.T) {
+	tests := []struct {
+		name      string
+		api       *fakeAPI
+		tokens    bool
+		requested int
+	}{
+		{name: "page numbers with total", api: &fakeAPI{total: 25, withTotal: true}, requested: 3},
+		{name: "page numbers with total on the edge", api: &fakeAPI{total: 20, withTotal: true}, requested: 2},
+		{name: "page numbers without total", api: &fakeAPI{total: 25}, requested: 3},
+		{name: "page numbers without total on the edge", api: &fakeAPI{total: 20}, requested: 3},
+		{name: "empty", api: &fakeAPI{total: 0, withTotal: true}, requested: 1},
+		{name: "tokens", api: &fakeAPI{total: 25}, tokens: true, requested: 3},
+		{name: "tokens on the edge", api: &fakeAPI{total: 20}, tokens: true, requested: 2},
+	}
+	for _, test := range tests {
+		fetch := test.api.byNumber
+		if test.tokens {
+			fetch = test.api.byToken
+		}
+		it := NewIterator(context.Background(), fetch, Page{Num: 1, Size: 10})
+		var got []int
+		for it.Next() {
+			got = append(got, it.Value())
+		}
+		assert.NoError(t, it.Err(), test.name)
+		assert.Equal(t, test.api.items(), append([]int{}, got...), test.name)
+		assert.Len(t, test.api.requests, test.requested, test.name)
+	}
+}
+
+func TestIterator_HasNext(t *testing.T) {
+	fetch := func(ctx context.Context, p Page) ([]string, Page, error) {
+		p.SetHasNext(p.Num < 3)
+		return []string{strconv.Itoa(p.Num)}, p, nil
+	}
+	var got []string
+	for v, err := range NewIterator(context.Background(), fetch, Page{Num: 1, Size: 1}).All() {
+		assert.NoError(t, err)
+		got = append(got, v)
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, got)
+}
+
+func TestIterator_Err(t *testing.T) {
+	failed := errors.New("failed")
+	fetch := func(ctx context.Context, p Page) ([]int, Page, error) {
+		if p.Num == 2 {
+			return nil, p, failed
+		}
+		return []int{1, 2}, Page{Num: p.Num, Size: 2, Total: 10}, nil
+	}
+
+	var got []int
+	var gotErr error
+	for v, err := range NewIterator(context.Background(), fetch, Page{Num: 1, Size: 2}).All() {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{1, 2}, got)
+	assert.Equal(t, failed, gotErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it := NewIterator(ctx, fetch, Page{Num: 1, Size: 2})
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, it.Err())
+
+	// breaking out of the loop stops fetching
+	api := &fakeAPI{total: 100}
+	for v := range NewIterator(context.Background(), api.byToken, Page{Size: 10}, WithTokens()).All() {
+		if v == 5 {
+			break
+		}
+	}
+	assert.Len(t, api.requests, 1)
+}
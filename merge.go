@@ -0,0 +1,273 @@
+package pagination
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Merger merges sources sorted the same way, e.g. shards of one table, into a
+// single sorted and paginated view.
+//
+// Each source is asked for the items starting at its page token, and must
+// return NextToken pointing after the returned items; a source returning no
+// NextToken is exhausted once its items are used. The position of every
+// source is kept in the NextToken of the merged page, so the following pages
+// continue where each source stopped.
+type Merger[T any] struct {
+	sources []FetchFunc[T]
+	config  sliceConfig[T]
+}
+
+// NewMerger returns a Merger ordering the items by Page.OrderBy, with the
+// fields resolved as in PaginateSlice.
+func NewMerger[T any](sources []FetchFunc[T], options ...SliceOption[T]) *Merger[T] {
+	m := &Merger[T]{sources: sources}
+	for i := range options {
+		options[i](&m.config)
+	}
+	return m
+}
+
+// mergeCursor is the position of one source.
+type mergeCursor struct {
+	Token string `json:"t,omitempty"`
+	// Skip is the number of items after Token which are already merged.
+	Skip  int  `json:"s,omitempty"`
+	Done  bool `json:"d,omitempty"`
+	Total int  `json:"n,omitempty"`
+}
+
+// mergeChunk is one page fetched from a source.
+type mergeChunk[T any] struct {
+	token string
+	items []T
+	next  string
+}
+
+// Fetch returns the merged page after p.Token, it can be used as the
+// FetchFunc of an Iterator.
+func (m *Merger[T]) Fetch(ctx context.Context, p Page) ([]T, Page, error) {
+	size := int(p.limit())
+	if size <= 0 {
+		return nil, p, ErrInvalidPageSize
+	}
+	keys, err := p.SortKeys()
+	if err != nil {
+		return nil, p, err
+	}
+	less := sliceLess(keys, &m.config)
+	if less == nil {
+		return nil, p, errors.Wrapf(ErrInvalidOrderBy, "can not merge by %q", p.OrderBy)
+	}
+
+	cursors, err := m.decode(p.Token)
+	if err != nil {
+		return nil, p, err
+	}
+
+	chunks := make([][]mergeChunk[T], len(m.sources))
+	pages := make([][]T, len(m.sources))
+	errs := make([]error, len(m.sources))
+	var wg sync.WaitGroup
+	for i := range m.sources {
+		if cursors[i].Done {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			chunks[i], pages[i], errs[i] = m.fetchSource(ctx, i, p, size, &cursors[i])
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, p, err
+		}
+	}
+
+	h := &mergeHeap[T]{less: less}
+	for i := range pages {
+		if len(pages[i]) > 0 {
+			h.heads = append(h.heads, mergeHead{source: i})
+			h.pages = append(h.pages, pages[i])
+		}
+	}
+	h.init()
+	items := make([]T, 0, size)
+	used := make([]int, len(m.sources))
+	for len(items) < size && h.Len() > 0 {
+		head := &h.heads[0]
+		items = append(items, h.pages[0][head.index])
+		used[head.source]++
+		head.index++
+		if head.index == len(h.pages[0]) {
+			h.pop()
+			continue
+		}
+		h.down(0)
+	}
+
+	total, done := 0, true
+	for i := range cursors {
+		if !cursors[i].Done {
+			advanceCursor(&cursors[i], chunks[i], used[i])
+		}
+		total += cursors[i].Total
+		done = done && cursors[i].Done
+	}
+
+	page := p
+	page.Size = size
+	page.SetTotal(total)
+	page.SetHasNext(!done)
+	page.NextToken = ""
+	if !done {
+		if page.NextToken, err = encodeMergeCursors(cursors); err != nil {
+			return nil, p, err
+		}
+	}
+	return items, page, nil
+}
+
+// fetchSource fetches chunks of the source until there are size unmerged
+// items after the cursor or the source is exhausted.
+func (m *Merger[T]) fetchSource(ctx context.Context, i int, p Page, size int, cursor *mergeCursor) ([]mergeChunk[T], []T, error) {
+	var chunks []mergeChunk[T]
+	var items []T
+	token, skip := cursor.Token, cursor.Skip
+	for {
+		req := p
+		req.Num, req.Size, req.Token, req.NextToken = 1, size, token, ""
+		got, page, err := m.sources[i](ctx, req)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "source %d", i)
+		}
+		cursor.Total = page.Total
+		chunks = append(chunks, mergeChunk[T]{token: token, items: got, next: page.NextToken})
+
+		if skip < len(got) {
+			items = append(items, got[skip:]...)
+			skip = 0
+		} else {
+			skip -= len(got)
+		}
+		if len(items) >= size || page.NextToken == "" || len(got) == 0 {
+			return chunks, items, nil
+		}
+		token = page.NextToken
+	}
+}
+
+// advanceCursor moves the cursor past the used items of the fetched chunks.
+// The new position is kept inside the chunk it falls in, so the next Fetch
+// reads at most the current chunk again.
+func advanceCursor[T any](c *mergeCursor, chunks []mergeChunk[T], used int) {
+	position := c.Skip + used
+	for _, chunk := range chunks {
+		if position < len(chunk.items) {
+			c.Token, c.Skip = chunk.token, position
+			return
+		}
+		position -= len(chunk.items)
+		if chunk.next == "" {
+			*c = mergeCursor{Done: true, Total: c.Total}
+			return
+		}
+		c.Token, c.Skip = chunk.next, position
+	}
+}
+
+func (m *Merger[T]) decode(token string) ([]mergeCursor, error) {
+	if token == "" {
+		return make([]mergeCursor, len(m.sources)), nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPageToken, err.Error())
+	}
+	var cursors []mergeCursor
+	if err := json.Unmarshal(data, &cursors); err != nil {
+		return nil, errors.Wrap(ErrInvalidPageToken, err.Error())
+	}
+	if len(cursors) != len(m.sources) {
+		return nil, errors.Wrapf(ErrInvalidPageToken, "%d sources in token, %d merged", len(cursors), len(m.sources))
+	}
+	return cursors, nil
+}
+
+func encodeMergeCursors(cursors []mergeCursor) (string, error) {
+	data, err := json.Marshal(cursors)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+type mergeHead struct {
+	source int
+	index  int
+}
+
+// mergeHeap is a min-heap holding the next item of every source, ties are
+// broken by the source order to keep the merge stable. It only shrinks.
+type mergeHeap[T any] struct {
+	less  func(a, b T) bool
+	heads []mergeHead
+	pages [][]T
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.heads) }
+
+func (h *mergeHeap[T]) lessAt(i, j int) bool {
+	a, b := h.pages[i][h.heads[i].index], h.pages[j][h.heads[j].index]
+	if h.less(a, b) {
+		return true
+	}
+	if h.less(b, a) {
+		return false
+	}
+	return h.heads[i].source < h.heads[j].source
+}
+
+func (h *mergeHeap[T]) swap(i, j int) {
+	h.heads[i], h.heads[j] = h.heads[j], h.heads[i]
+	h.pages[i], h.pages[j] = h.pages[j], h.pages[i]
+}
+
+func (h *mergeHeap[T]) init() {
+	for i := h.Len()/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+}
+
+// down moves the head at i down until its children sort after it.
+func (h *mergeHeap[T]) down(i int) {
+	n := h.Len()
+	for {
+		least, left, right := i, 2*i+1, 2*i+2
+		if left < n && h.lessAt(left, least) {
+			least = left
+		}
+		if right < n && h.lessAt(right, least) {
+			least = right
+		}
+		if least == i {
+			return
+		}
+		h.swap(i, least)
+		i = least
+	}
+}
+
+// pop removes the first head.
+func (h *mergeHeap[T]) pop() {
+	n := h.Len() - 1
+	h.swap(0, n)
+	h.heads, h.pages = h.heads[:n], h.pages[:n]
+	h.down(0)
+}
@@ -0,0 +1,134 @@
+package pagination
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type shardItem struct {
+	Shard int
+	Score int
+}
+
+// shardSource serves sorted items by offset tokens and counts the items it served.
+func shardSource(items []shardItem, served *int) FetchFunc[shardItem] {
+	return func(ctx context.Context, p Page) ([]shardItem, Page, error) {
+		start := 0
+		if p.Token != "" {
+			start, _ = strconv.Atoi(p.Token)
+		}
+		end := start + p.Size
+		if end > len(items) {
+			end = len(items)
+		}
+		*served += end - start
+		page := Page{Size: p.Size, Total: len(items)}
+		if end < len(items) {
+			page.NextToken = strconv.Itoa(end)
+		}
+		return items[start:end], page, nil
+	}
+}
+
+func TestMerger(t *testing.T) {
+	for _, descending := range []bool{false, true} {
+		var all []shardItem
+		var sources []FetchFunc[shardItem]
+		served := make([]int, 3)
+		for shard, n := range []int{17, 5, 30} {
+			var items []shardItem
+			for i := 0; i < n; i++ {
+				items = append(items, shardItem{Shard: shard, Score: (i * (shard + 3)) % 41})
+			}
+			sort.SliceStable(items, func(i, j int) bool {
+				if descending {
+					return items[i].Score > items[j].Score
+				}
+				return items[i].Score < items[j].Score
+			})
+			all = append(all, items...)
+			sources = append(sources, shardSource(items, &served[shard]))
+		}
+		sort.SliceStable(all, func(i, j int) bool {
+			if all[i].Score != all[j].Score {
+				if descending {
+					return all[i].Score > all[j].Score
+				}
+				return all[i].Score < all[j].Score
+			}
+			return all[i].Shard < all[j].Shard
+		})
+
+		requests := 0
+		merger := NewMerger(sources)
+		fetch := func(ctx context.Context, p Page) ([]shardItem, Page, error) {
+			requests++
+			return merger.Fetch(ctx, p)
+		}
+		it := NewIterator(context.Background(), fetch, Page{Size: 7, OrderBy: "Score", IsDescending: descending})
+		var merged []shardItem
+		for it.Next() {
+			merged = append(merged, it.Value())
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, all, merged)
+		assert.Equal(t, 52, it.Page().Total)
+		assert.Empty(t, it.Page().NextToken)
+
+		// a source never rescans from its start, each request reads at most two pages again
+		for shard, n := range []int{17, 5, 30} {
+			assert.LessOrEqual(t, served[shard], n+2*requests*7, shard)
+		}
+	}
+}
+
+func TestMerger_Errors(t *testing.T) {
+	served := 0
+	merger := NewMerger([]FetchFunc[shardItem]{shardSource(nil, &served)})
+	ctx := context.Background()
+
+	_, _, err := merger.Fetch(ctx, Page{OrderBy: "Score"})
+	assert.ErrorIs(t, err, ErrInvalidPageSize)
+	_, _, err = merger.Fetch(ctx, Page{Size: 10, OrderBy: "Unknown"})
+	assert.ErrorIs(t, err, ErrInvalidOrderBy)
+	_, _, err = merger.Fetch(ctx, Page{Size: 10, OrderBy: "Score", Token: "!!"})
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+	token, _ := encodeMergeCursors(make([]mergeCursor, 2))
+	_, _, err = merger.Fetch(ctx, Page{Size: 10, OrderBy: "Score", Token: token})
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+
+	items, page, err := merger.Fetch(ctx, Page{Size: 10, OrderBy: "Score"})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+	assert.False(t, page.HasNext())
+}
+
+func TestMergeHeap(t *testing.T) {
+	h := &mergeHeap[int]{less: func(a, b int) bool { return a < b }}
+	for i, page := range [][]int{{3, 5, 9}, {1, 5}, {5, 6}, {2}, {0, 5, 5, 7}, {4}} {
+		h.heads = append(h.heads, mergeHead{source: i})
+		h.pages = append(h.pages, page)
+	}
+	h.init()
+
+	var merged []int
+	var sources []int
+	for h.Len() > 0 {
+		head := &h.heads[0]
+		merged = append(merged, h.pages[0][head.index])
+		sources = append(sources, head.source)
+		head.index++
+		if head.index == len(h.pages[0]) {
+			h.pop()
+			continue
+		}
+		h.down(0)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 5, 5, 5, 5, 6, 7, 9}, merged)
+	// equal items keep the source order
+	assert.Equal(t, []int{4, 1, 3, 0, 5, 0, 1, 2, 4, 4, 2, 4, 0}, sources)
+}
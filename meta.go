@@ -0,0 +1,155 @@
+package pagination
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// KeyNaming is the naming of the keys FillResponse sets in maps.
+type KeyNaming int
+
+const (
+	// SnakeCase names keys like page_num, which is the default.
+	SnakeCase KeyNaming = iota
+	// CamelCase names keys like pageNum, as the protobuf JSON mapping does.
+	CamelCase
+)
+
+// WithKeyNaming sets the naming of the keys FillResponse sets in maps.
+func WithKeyNaming(naming KeyNaming) Option {
+	return func(p *Page) error {
+		p.keyNaming = naming
+		return nil
+	}
+}
+
+// Meta is the pagination metadata of a page, for embedding in responses which
+// aren't filled by FillResponse. Total and LastPage are nil when the total is
+// unknown, see WithProbe.
+type Meta struct {
+	PageNum         int64  `json:"page_num"`
+	PageSize        int64  `json:"page_size"`
+	Total           *int64 `json:"total,omitempty"`
+	LastPage        *int64 `json:"last_page,omitempty"`
+	HasNext         bool   `json:"has_next"`
+	NextPageToken   string `json:"next_page_token,omitempty"`
+	TotalIsCapped   bool   `json:"total_is_capped,omitempty"`
+	TotalIsEstimate bool   `json:"total_is_estimate,omitempty"`
+}
+
+// Meta returns the metadata of the page, filled the same way as FillResponse does.
+func (p Page) Meta() Meta {
+	m := Meta{
+		PageNum:         int64(p.Num),
+		PageSize:        int64(p.Size),
+		HasNext:         p.HasNext(),
+		NextPageToken:   p.NextToken,
+		TotalIsCapped:   p.TotalIsCapped,
+		TotalIsEstimate: p.TotalIsEstimate,
+	}
+	if p.totalKnown() {
+		total, lastPage := int64(p.Total), int64(p.LastPage())
+		m.Total, m.LastPage = &total, &lastPage
+		if p.Size == 0 {
+			m.PageSize = total
+		}
+	}
+	return m
+}
+
+var _rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// isResponseMap reports whether t is a map FillResponse can fill, that is a
+// map from strings to interface{} or to json.RawMessage.
+func isResponseMap(t reflect.Type) bool {
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return false
+	}
+	elem := t.Elem()
+	return elem.Kind() == reflect.Interface && elem.NumMethod() == 0 || elem == _rawMessageType
+}
+
+// key returns the map key of a snake_case or Go field name.
+func (p Page) key(name string) string {
+	if p.keyNaming == CamelCase {
+		return lowerCamelCase(snakeCase(name))
+	}
+	return snakeCase(name)
+}
+
+// snakeCase converts a Go name to snake_case, e.g. PageNum to page_num.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// mapContainer returns the map nested in m under one of the keywords, or m
+// itself.
+func (p Page) mapContainer(m reflect.Value, keywords []string) reflect.Value {
+	for _, word := range keywords {
+		nested := m.MapIndex(reflect.ValueOf(p.key(word)).Convert(m.Type().Key()))
+		for nested.IsValid() && nested.Kind() == reflect.Interface && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+		if nested.IsValid() && isResponseMap(nested.Type()) && !nested.IsNil() {
+			return p.mapContainer(nested, keywords)
+		}
+	}
+	return m
+}
+
+// fillMap sets the metadata of the page in the map m, in the order of Meta.
+func (p Page) fillMap(m reflect.Value) error {
+	if m.IsNil() {
+		if !m.CanSet() {
+			return ErrInvalidResponse
+		}
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	meta := p.Meta()
+	entries := []struct {
+		name  string
+		value interface{}
+		set   bool
+	}{
+		{"page_num", meta.PageNum, true},
+		{"page_size", meta.PageSize, true},
+		{"total", meta.Total, meta.Total != nil},
+		{"last_page", meta.LastPage, meta.LastPage != nil},
+		{"has_next", meta.HasNext, true},
+		{"next_page_token", meta.NextPageToken, meta.NextPageToken != ""},
+		{"total_is_capped", meta.TotalIsCapped, meta.TotalIsCapped},
+		{"total_is_estimate", meta.TotalIsEstimate, meta.TotalIsEstimate},
+	}
+	for _, e := range entries {
+		if !e.set {
+			continue
+		}
+		value := e.value
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+			value = v.Elem().Interface()
+		}
+		elem := reflect.ValueOf(value)
+		if m.Type().Elem() == _rawMessageType {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			elem = reflect.ValueOf(json.RawMessage(data))
+		}
+		key := reflect.ValueOf(p.key(e.name)).Convert(m.Type().Key())
+		m.SetMapIndex(key, elem.Convert(m.Type().Elem()))
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package pagination
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPage_Meta(t *testing.T) {
+	page := Page{Num: 2, Size: 10, NextToken: "next"}
+	page.SetTotal(25)
+	data, err := json.Marshal(page.Meta())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"page_num":2,"page_size":10,"total":25,"last_page":3,"has_next":true,"next_page_token":"next"}`, string(data))
+
+	// the total is left out when it is unknown
+	page, err = Parse(&testRequest{PageNum: 2, PageSize: 10}, WithProbe())
+	assert.NoError(t, err)
+	page.Trim(5)
+	data, err = json.Marshal(page.Meta())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"page_num":2,"page_size":10,"has_next":false}`, string(data))
+
+	envelope := struct {
+		Data []string `json:"data"`
+		Meta Meta     `json:"meta"`
+	}{Data: []string{"a"}, Meta: Page{Num: 1, Size: 5, Total: 1}.Meta()}
+	data, err = json.Marshal(envelope)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"data":["a"],"meta":{"page_num":1,"page_size":5,"total":1,"last_page":1,"has_next":false}}`, string(data))
+}
+
+func TestPage_FillMap(t *testing.T) {
+	page := Page{Num: 2, Size: 10}
+	page.SetTotal(25)
+
+	resp := map[string]interface{}{"data": []string{"a"}}
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, map[string]interface{}{
+		"data":      []string{"a"},
+		"page_num":  int64(2),
+		"page_size": int64(10),
+		"total":     int64(25),
+		"last_page": int64(3),
+		"has_next":  true,
+	}, resp)
+
+	// nested under a keyword
+	nested := map[string]any{"pagination": map[string]any{}}
+	assert.NoError(t, page.FillResponse(&nested))
+	assert.Equal(t, int64(25), nested["pagination"].(map[string]any)["total"])
+	assert.NotContains(t, nested, "total")
+
+	// camelCase keys
+	page, err := Parse(&testRequest{PageNum: 2, PageSize: 10}, WithKeyNaming(CamelCase))
+	assert.NoError(t, err)
+	page.NextToken = "next"
+	camel := map[string]any{}
+	assert.NoError(t, page.FillResponse(camel))
+	assert.Equal(t, map[string]any{
+		"pageNum":       int64(2),
+		"pageSize":      int64(10),
+		"total":         int64(0),
+		"lastPage":      int64(0),
+		"hasNext":       false,
+		"nextPageToken": "next",
+	}, camel)
+
+	// json.RawMessage envelopes
+	raw := map[string]json.RawMessage{"data": json.RawMessage(`[]`)}
+	assert.NoError(t, page.FillResponse(raw))
+	assert.Equal(t, json.RawMessage(`"next"`), raw["nextPageToken"])
+	assert.Equal(t, json.RawMessage(`2`), raw["pageNum"])
+
+	// maps inside structs and paths
+	withMeta := &struct{ Data struct{ Meta map[string]any } }{}
+	assert.NoError(t, page.FillResponseAt(withMeta, "Data.Meta"))
+	assert.Equal(t, int64(10), withMeta.Data.Meta["pageSize"])
+	paths := map[string]any{"users": map[string]any{}}
+	assert.NoError(t, page.FillResponseAt(paths, "users"))
+	assert.Equal(t, int64(10), paths["users"].(map[string]any)["pageSize"])
+
+	var nilMap map[string]any
+	assert.ErrorIs(t, page.FillResponse(nilMap), ErrInvalidResponse)
+	assert.NoError(t, page.FillResponse(&nilMap))
+	assert.Equal(t, int64(2), nilMap["pageNum"])
+	assert.ErrorIs(t, page.FillResponse(map[string]int{}), ErrInvalidResponse)
+}
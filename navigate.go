@@ -0,0 +1,74 @@
+package pagination
+
+// The navigation methods return a new Page and leave the receiver untouched.
+// Page numbers are kept between 1 and LastPage when the total is known, i.e.
+// set by SetTotal or non-zero.
+
+// Next returns the following page. A page token moves on to NextToken.
+func (p Page) Next() Page {
+	num := p.Num + 1
+	if p.Num < 1 {
+		num = 2
+	}
+	next := p.moveTo(num)
+	if p.NextToken != "" {
+		next.Token = p.NextToken
+	}
+	return next
+}
+
+func (p Page) Prev() Page {
+	return p.moveTo(p.Num - 1)
+}
+
+func (p Page) First() Page {
+	return p.moveTo(1)
+}
+
+func (p Page) Last() Page {
+	return p.moveTo(p.LastPage())
+}
+
+func (p Page) Goto(num int) Page {
+	return p.moveTo(num)
+}
+
+// WithSize changes the page size and moves to the page showing the first item
+// of the current page. Sizes less than 1 are ignored.
+func (p Page) WithSize(size int) Page {
+	if size < 1 {
+		return p
+	}
+	first := p.Offset64()
+	p.Size = size
+	return p.moveTo(int(first/int64(size)) + 1)
+}
+
+// Contains reports whether the item at the given zero-based index is on this page.
+func (p Page) Contains(index int) bool {
+	offset := p.Offset64()
+	if int64(index) < offset || (p.bounded() && index >= p.Total) {
+		return false
+	}
+	limit := p.limit()
+	return limit == 0 || int64(index) < addInt64(offset, limit)
+}
+
+func (p Page) moveTo(num int) Page {
+	if p.bounded() && p.Size > 0 && num > p.LastPage() {
+		num = p.LastPage()
+	}
+	if num < 1 {
+		num = 1
+	}
+	p.Num = num
+	p.Token, p.NextToken = "", ""
+	p.hasNext, p.hasNextSet = false, false
+	return p
+}
+
+// bounded reports whether Total bounds the navigation. A zero Total is
+// usually not set yet, unless SetTotal says so.
+func (p Page) bounded() bool {
+	return p.totalSet || (!p.probe && p.Total > 0)
+}
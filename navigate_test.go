@@ -0,0 +1,88 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPage_Navigate(t *testing.T) {
+	p := Page{Num: 3, Size: 10, Total: 45}
+	assert.Equal(t, 4, p.Next().Num)
+	assert.Equal(t, 5, p.Next().Next().Next().Num)
+	assert.Equal(t, 2, p.Prev().Num)
+	assert.Equal(t, 1, p.First().Prev().Num)
+	assert.Equal(t, 5, p.Last().Num)
+	assert.Equal(t, 4, p.Goto(4).Num)
+	assert.Equal(t, 5, p.Goto(100).Num)
+	assert.Equal(t, 1, p.Goto(-1).Num)
+	assert.Equal(t, 3, p.Num)
+
+	// without a total there is no upper bound
+	assert.Equal(t, 100, Page{Num: 1, Size: 10}.Goto(100).Num)
+	assert.Equal(t, 2, Page{Size: 10}.Next().Num)
+
+	// an empty result set stays on the first page
+	var empty Page
+	empty.Size = 10
+	empty.SetTotal(0)
+	assert.Equal(t, 1, empty.Next().Num)
+	assert.Equal(t, 1, empty.Last().Num)
+
+	next := Page{Num: 1, Size: 10, Token: "a", NextToken: "b"}.Next()
+	assert.Equal(t, "b", next.Token)
+	assert.Equal(t, "", next.NextToken)
+	assert.Equal(t, "", next.Prev().Token)
+
+	p.SetHasNext(true)
+	assert.False(t, p.Goto(5).hasNextSet)
+}
+
+func TestPage_WithSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     Page
+		size     int
+		excepted Page
+	}{
+		{
+			name:     "smaller",
+			page:     Page{Num: 3, Size: 10, Total: 100},
+			size:     5,
+			excepted: Page{Num: 5, Size: 5, Total: 100},
+		},
+		{
+			name:     "bigger",
+			page:     Page{Num: 4, Size: 10, Total: 100},
+			size:     25,
+			excepted: Page{Num: 2, Size: 25, Total: 100},
+		},
+		{
+			name:     "bigger than the total",
+			page:     Page{Num: 4, Size: 10, Total: 100},
+			size:     200,
+			excepted: Page{Num: 1, Size: 200, Total: 100},
+		},
+		{
+			name:     "ignored",
+			page:     Page{Num: 4, Size: 10, Total: 100},
+			size:     0,
+			excepted: Page{Num: 4, Size: 10, Total: 100},
+		},
+	}
+	for _, test := range tests {
+		p := test.page.WithSize(test.size)
+		assert.Equal(t, test.excepted, p, test.name)
+		assert.True(t, p.Contains(int(test.page.Offset())), test.name)
+	}
+}
+
+func TestPage_Contains(t *testing.T) {
+	p := Page{Num: 2, Size: 10, Total: 15}
+	assert.False(t, p.Contains(9))
+	assert.True(t, p.Contains(10))
+	assert.True(t, p.Contains(14))
+	assert.False(t, p.Contains(15))
+	assert.False(t, Page{Num: 2, Size: 10, Total: 100}.Contains(20))
+	assert.True(t, Page{}.Contains(1000))
+}
@@ -0,0 +1,178 @@
+package pagination
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Nulls is the position of NULL values in a sort.
+type Nulls int
+
+const (
+	// NullsDefault leaves the position of NULL values to the database.
+	NullsDefault Nulls = iota
+	NullsFirst
+	NullsLast
+)
+
+// SortKey is one column of a multi-column sort.
+type SortKey struct {
+	Field      string
+	Descending bool
+	Nulls      Nulls
+}
+
+// ParseOrderBy parses an order such as "name, created_at desc nulls last".
+// A field prefixed with "-" is descending, fields without a direction are
+// descending when descending is true.
+func ParseOrderBy(orderBy string, descending bool) ([]SortKey, error) {
+	if strings.TrimSpace(orderBy) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	for _, term := range strings.Split(orderBy, ",") {
+		words := strings.Fields(term)
+		if len(words) == 0 {
+			return nil, errors.Wrapf(ErrInvalidOrderBy, "empty term in %q", orderBy)
+		}
+
+		key := SortKey{Field: words[0], Descending: descending}
+		if strings.HasPrefix(key.Field, "-") {
+			key.Field = key.Field[1:]
+			key.Descending = true
+		}
+		if key.Field == "" {
+			return nil, errors.Wrapf(ErrInvalidOrderBy, "empty field in %q", orderBy)
+		}
+
+		rest := words[1:]
+		if len(rest) > 0 {
+			switch strings.ToLower(rest[0]) {
+			case "asc":
+				key.Descending = false
+				rest = rest[1:]
+			case "desc":
+				key.Descending = true
+				rest = rest[1:]
+			}
+		}
+		if len(rest) > 0 {
+			if len(rest) != 2 || !strings.EqualFold(rest[0], "nulls") {
+				return nil, errors.Wrapf(ErrInvalidOrderBy, "unexpected %q", strings.Join(rest, " "))
+			}
+			switch strings.ToLower(rest[1]) {
+			case "first":
+				key.Nulls = NullsFirst
+			case "last":
+				key.Nulls = NullsLast
+			default:
+				return nil, errors.Wrapf(ErrInvalidOrderBy, "unexpected nulls %q", rest[1])
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// sortOrderBy renders sort fields as an order for ParseOrderBy. Fields
+// without a direction follow IsDescending.
+func sortOrderBy(sort []*SortField) (string, error) {
+	terms := make([]string, 0, len(sort))
+	for _, f := range sort {
+		field := strings.TrimSpace(f.GetField())
+		if field == "" || strings.ContainsAny(field, ", \t") {
+			return "", errors.Wrapf(ErrInvalidOrderBy, "invalid sort field %q", f.GetField())
+		}
+		term := field
+		switch f.GetDirection() {
+		case SortField_DIRECTION_UNSPECIFIED:
+		case SortField_ASC:
+			term += " asc"
+		case SortField_DESC:
+			term += " desc"
+		default:
+			return "", errors.Wrapf(ErrInvalidOrderBy, "invalid direction %d of %q", f.GetDirection(), field)
+		}
+		switch f.GetNulls() {
+		case SortField_NULLS_UNSPECIFIED:
+		case SortField_NULLS_FIRST:
+			term += " nulls first"
+		case SortField_NULLS_LAST:
+			term += " nulls last"
+		default:
+			return "", errors.Wrapf(ErrInvalidOrderBy, "invalid nulls %d of %q", f.GetNulls(), field)
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, ", "), nil
+}
+
+// SortOrderBy returns the order of the sort fields, or orderBy when there
+// are none, the way Parse reads sort and order_by. It is used by generated code.
+func SortOrderBy(sort []*SortField, orderBy string) (string, error) {
+	if len(sort) == 0 {
+		return orderBy, nil
+	}
+	return sortOrderBy(sort)
+}
+
+// SortFields returns the order of the page as sort fields with explicit
+// directions, nil when the page has no valid order.
+func (p Page) SortFields() []*SortField {
+	keys, err := p.SortKeys()
+	if err != nil || len(keys) == 0 {
+		return nil
+	}
+	sort := make([]*SortField, len(keys))
+	for i, k := range keys {
+		sort[i] = &SortField{Field: k.Field, Direction: SortField_ASC}
+		if k.Descending {
+			sort[i].Direction = SortField_DESC
+		}
+		switch k.Nulls {
+		case NullsFirst:
+			sort[i].Nulls = SortField_NULLS_FIRST
+		case NullsLast:
+			sort[i].Nulls = SortField_NULLS_LAST
+		}
+	}
+	return sort
+}
+
+// keysOrderBy renders sort keys as an order with explicit directions.
+func keysOrderBy(keys []SortKey) string {
+	terms := make([]string, len(keys))
+	for i, k := range keys {
+		terms[i] = k.format(true)
+	}
+	return strings.Join(terms, ", ")
+}
+
+// SortKeys parses OrderBy and IsDescending, see ParseOrderBy.
+func (p Page) SortKeys() ([]SortKey, error) {
+	return ParseOrderBy(p.OrderBy, p.IsDescending)
+}
+
+func (k SortKey) String() string {
+	return k.format(false)
+}
+
+// format renders the key, ascending keys get an explicit "asc" when explicit
+// is set.
+func (k SortKey) format(explicit bool) string {
+	s := k.Field
+	if k.Descending {
+		s += " desc"
+	} else if explicit {
+		s += " asc"
+	}
+	switch k.Nulls {
+	case NullsFirst:
+		s += " nulls first"
+	case NullsLast:
+		s += " nulls last"
+	}
+	return s
+}
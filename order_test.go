@@ -0,0 +1,153 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrderBy(t *testing.T) {
+	tests := []struct {
+		name       string
+		orderBy    string
+		descending bool
+		excepted   []SortKey
+		err        error
+	}{
+		{
+			name:    "empty",
+			orderBy: " ",
+		},
+		{
+			name:     "single",
+			orderBy:  "id",
+			excepted: []SortKey{{Field: "id"}},
+		},
+		{
+			name:       "single descending",
+			orderBy:    "id",
+			descending: true,
+			excepted:   []SortKey{{Field: "id", Descending: true}},
+		},
+		{
+			name:       "explicit directions win",
+			orderBy:    "name ASC, -created_at, id desc nulls last, score nulls FIRST",
+			descending: false,
+			excepted: []SortKey{
+				{Field: "name"},
+				{Field: "created_at", Descending: true},
+				{Field: "id", Descending: true, Nulls: NullsLast},
+				{Field: "score", Nulls: NullsFirst},
+			},
+		},
+		{
+			name:    "empty term",
+			orderBy: "id,,name",
+			err:     ErrInvalidOrderBy,
+		},
+		{
+			name:    "garbage",
+			orderBy: "id; DROP TABLE users",
+			err:     ErrInvalidOrderBy,
+		},
+		{
+			name:    "bad nulls",
+			orderBy: "id nulls middle",
+			err:     ErrInvalidOrderBy,
+		},
+	}
+	for _, test := range tests {
+		keys, err := ParseOrderBy(test.orderBy, test.descending)
+		assert.ErrorIs(t, err, test.err, test.name)
+		assert.Equal(t, test.excepted, keys, test.name)
+	}
+}
+
+func TestParse_Sort(t *testing.T) {
+	req := &PaginationRequest{
+		OrderBy:      "ignored",
+		IsDescending: true,
+		Sort: []*SortField{
+			{Field: "name", Direction: SortField_ASC, Nulls: SortField_NULLS_LAST},
+			{Field: "created_at"},
+			{Field: "id", Direction: SortField_DESC, Nulls: SortField_NULLS_FIRST},
+		},
+		Filter:    "age > 18",
+		PageToken: "t",
+		ShowTotal: true,
+	}
+	page, err := Parse(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "name asc nulls last, created_at, id desc nulls first", page.OrderBy)
+	assert.Equal(t, "age > 18", page.Filter)
+	assert.Equal(t, "t", page.Token)
+	assert.True(t, page.ShowTotal)
+	keys, err := page.SortKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, []SortKey{
+		{Field: "name", Nulls: NullsLast},
+		{Field: "created_at", Descending: true},
+		{Field: "id", Descending: true, Nulls: NullsFirst},
+	}, keys)
+
+	// the same through protoreflect
+	page, err = ParseProto(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "name asc nulls last, created_at, id desc nulls first", page.OrderBy)
+	assert.Equal(t, "age > 18", page.Filter)
+	assert.True(t, page.ShowTotal)
+
+	// sort fields of the page parse back to it
+	sorted, err := Parse(&PaginationRequest{Sort: page.SortFields()})
+	assert.NoError(t, err)
+	assert.Equal(t, "name asc nulls last, created_at desc, id desc nulls first", sorted.OrderBy)
+	orderBy, err := SortOrderBy(nil, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "id", orderBy)
+	assert.Nil(t, Page{OrderBy: "name sideways"}.SortFields())
+
+	// without sort order_by is used
+	page, err = Parse(&PaginationRequest{OrderBy: "name"})
+	assert.NoError(t, err)
+	assert.Equal(t, "name", page.OrderBy)
+
+	// sort keys of custom requests keep their direction
+	page, err = Parse(struct {
+		Sort         []SortKey
+		OrderBy      string
+		IsDescending bool
+	}{Sort: []SortKey{{Field: "name"}, {Field: "id", Descending: true, Nulls: NullsLast}}, IsDescending: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "name asc, id desc nulls last", page.OrderBy)
+	keys, err = page.SortKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, []SortKey{{Field: "name"}, {Field: "id", Descending: true, Nulls: NullsLast}}, keys)
+
+	for _, sort := range [][]*SortField{
+		{{Field: ""}},
+		{{Field: "first name"}},
+		{{Field: "name", Direction: 7}},
+		{{Field: "name", Nulls: 7}},
+	} {
+		_, err = Parse(&PaginationRequest{Sort: sort})
+		assert.ErrorIs(t, err, ErrInvalidOrderBy)
+		_, err = ParseProto(&PaginationRequest{Sort: sort})
+		assert.ErrorIs(t, err, ErrInvalidOrderBy)
+	}
+
+	// other fields with these names are left alone
+	page, err = Parse(&struct {
+		PageNum, PageSize int
+		Sort              string
+		Filter            map[string]string
+		ShowTotal         string
+	}{1, 10, "name", map[string]string{"a": "b"}, "yes"})
+	assert.NoError(t, err)
+	assert.Equal(t, Page{Num: 1, Size: 10, defaultSize: 15}, page)
+	page, err = Parse(struct {
+		OrderBy string
+		Sort    []string
+	}{OrderBy: "id", Sort: []string{"name"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "id", page.OrderBy)
+}
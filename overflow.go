@@ -0,0 +1,144 @@
+package pagination
+
+import (
+	"math"
+	"reflect"
+)
+
+// OverflowPolicy decides what happens when the arithmetic of a page doesn't
+// fit in the integer type it is returned in.
+type OverflowPolicy int
+
+const (
+	// OverflowClamp clamps the results to the largest or smallest value of
+	// their type, and negative offsets to 0, which is the default.
+	OverflowClamp OverflowPolicy = iota
+	// OverflowError makes Parse reject pages whose Offset is negative or
+	// whose Offset or Limit don't fit in int32, and FillResponse fail on numbers a field can't hold.
+	OverflowError
+)
+
+// WithOverflowPolicy sets the overflow policy of the page, see OverflowPolicy.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(p *Page) error {
+		p.overflow = policy
+		return nil
+	}
+}
+
+// checkOverflow returns ErrOffsetOverflow when the policy is OverflowError and
+// Offset or Limit would be clamped, negative offsets included.
+func (p Page) checkOverflow() error {
+	if p.overflow != OverflowError {
+		return nil
+	}
+	offset, ok := p.offset64()
+	if !ok || offset > math.MaxInt32 || offset < 0 {
+		return ErrOffsetOverflow
+	}
+	if limit := p.Limit64(); limit > math.MaxInt32 || limit < math.MinInt32 {
+		return ErrOffsetOverflow
+	}
+	return nil
+}
+
+// mulInt64 returns a*b, ok is false on overflow.
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if (c < 0) != ((a < 0) != (b < 0)) || c/b != a {
+		return c, false
+	}
+	return c, true
+}
+
+// addInt64 returns a+b clamped to the int64 range.
+func addInt64(a, b int64) int64 {
+	c := a + b
+	switch {
+	case a > 0 && b > 0 && c < 0:
+		return math.MaxInt64
+	case a < 0 && b < 0 && c >= 0:
+		return math.MinInt64
+	}
+	return c
+}
+
+func clampInt32(n int64) int32 {
+	switch {
+	case n > math.MaxInt32:
+		return math.MaxInt32
+	case n < math.MinInt32:
+		return math.MinInt32
+	}
+	return int32(n)
+}
+
+// Clamp converts n to T, clamped to the range of T like FillProto does under
+// OverflowClamp. It is used by generated code.
+func Clamp[T int32 | int64 | uint32 | uint64](n int64) T {
+	switch any(T(0)).(type) {
+	case int32:
+		return T(clampInt32(n))
+	case uint32:
+		return T(max(0, min(n, math.MaxUint32)))
+	case uint64:
+		return T(max(0, n))
+	}
+	return T(n)
+}
+
+// toInt64 converts a number to int64, overflow is true when it was clamped.
+func toInt64(v reflect.Value) (n int64, ok, overflow bool) {
+	switch {
+	case v.CanInt():
+		return v.Int(), true, false
+	case v.CanUint():
+		if u := v.Uint(); u > math.MaxInt64 {
+			return math.MaxInt64, true, true
+		}
+		return int64(v.Uint()), true, false
+	case v.CanConvert(reflect.TypeOf(int64(0))):
+		return v.Convert(reflect.TypeOf(int64(0))).Int(), true, false
+	}
+	return 0, false, false
+}
+
+func toFloat64(v reflect.Value) (float64, bool) {
+	switch {
+	case v.CanFloat():
+		return v.Float(), true
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	}
+	return 0, false
+}
+
+// clampInt clamps n to the range of the signed integer value f.
+func clampInt(f reflect.Value, n int64) int64 {
+	bits := f.Type().Bits()
+	max := int64(1)<<(bits-1) - 1
+	min := -max - 1
+	switch {
+	case n > max:
+		return max
+	case n < min:
+		return min
+	}
+	return n
+}
+
+// clampUint clamps n to the range of the unsigned integer value f.
+func clampUint(f reflect.Value, n int64) uint64 {
+	if n < 0 {
+		return 0
+	}
+	if max := uint64(1)<<f.Type().Bits() - 1; uint64(n) > max {
+		return max
+	}
+	return uint64(n)
+}
@@ -0,0 +1,253 @@
+package pagination
+
+import (
+	"database/sql"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestPage_Offset64(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     Page
+		offset   int32
+		offset64 int64
+	}{
+		{
+			name:     "fits in int32",
+			page:     Page{Num: 2, Size: math.MaxInt32 - 1},
+			offset:   math.MaxInt32 - 1,
+			offset64: math.MaxInt32 - 1,
+		},
+		{
+			name:     "int32 boundary",
+			page:     Page{Num: 2, Size: math.MaxInt32},
+			offset:   math.MaxInt32,
+			offset64: math.MaxInt32,
+		},
+		{
+			name:     "beyond int32",
+			page:     Page{Num: math.MaxInt32, Size: math.MaxInt32},
+			offset:   math.MaxInt32,
+			offset64: (math.MaxInt32 - 1) * math.MaxInt32,
+		},
+		{
+			name:     "int64 boundary",
+			page:     Page{Num: 2, Size: math.MaxInt64},
+			offset:   math.MaxInt32,
+			offset64: math.MaxInt64,
+		},
+		{
+			name:     "beyond int64",
+			page:     Page{Num: math.MaxInt64, Size: 3},
+			offset:   math.MaxInt32,
+			offset64: math.MaxInt64,
+		},
+		{
+			name:     "negative size beyond int64",
+			page:     Page{Num: math.MaxInt64, Size: -3},
+			offset:   0,
+			offset64: 0,
+		},
+		{
+			name:     "negative size",
+			page:     Page{Num: 3, Size: -1},
+			offset:   0,
+			offset64: 0,
+		},
+		{
+			name:     "negative page number",
+			page:     Page{Num: -3, Size: 10},
+			offset:   0,
+			offset64: 0,
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.offset, test.page.Offset(), test.name)
+		assert.Equal(t, test.offset64, test.page.Offset64(), test.name)
+	}
+
+	p := Page{Num: 1, Size: math.MaxInt32, probe: true}
+	assert.Equal(t, int32(math.MaxInt32), p.Limit())
+	assert.Equal(t, int64(math.MaxInt32)+1, p.Limit64())
+	p.Size = math.MaxInt64
+	assert.Equal(t, int64(math.MaxInt64), p.Limit64())
+
+	p = Page{Num: math.MaxInt64, Size: 10}
+	p.SetTotal(math.MaxInt64)
+	assert.False(t, p.HasNext())
+	assert.Equal(t, math.MaxInt64/10+1, p.LastPage())
+}
+
+func TestParse_OverflowPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		req  testRequest
+		err  error
+	}{
+		{name: "int32 boundary", req: testRequest{PageNum: 2, PageSize: math.MaxInt32}},
+		{name: "beyond int32", req: testRequest{PageNum: 3, PageSize: math.MaxInt32}, err: ErrOffsetOverflow},
+		{name: "beyond int64", req: testRequest{PageNum: math.MaxInt64, PageSize: 3}, err: ErrOffsetOverflow},
+		{name: "limit beyond int32", req: testRequest{PageNum: 1, PageSize: math.MaxInt32 + 1}, err: ErrOffsetOverflow},
+		{name: "negative offset", req: testRequest{PageNum: 3, PageSize: -1}, err: ErrOffsetOverflow},
+	}
+	for _, test := range tests {
+		_, err := Parse(&test.req, WithOverflowPolicy(OverflowError))
+		assert.Equal(t, test.err, err, test.name)
+		_, err = Parse(&test.req)
+		assert.NoError(t, err, test.name)
+	}
+
+	_, err := Parse(&testRequest{PageNum: 1, PageSize: math.MaxInt32}, WithOverflowPolicy(OverflowError), WithProbe())
+	assert.Equal(t, ErrOffsetOverflow, err)
+}
+
+func TestSetNumber_Overflow(t *testing.T) {
+	var i8 int8
+	var u8 uint8
+	var i32 int32
+	var u64 uint64
+	var i64 int64
+	tests := []struct {
+		name    string
+		field   interface{}
+		number  interface{}
+		err     error
+		clamped interface{}
+	}{
+		{name: "int8 max", field: &i8, number: 127, clamped: int8(127)},
+		{name: "int8 overflow", field: &i8, number: 128, err: ErrNumberOverflow, clamped: int8(127)},
+		{name: "int8 underflow", field: &i8, number: -129, err: ErrNumberOverflow, clamped: int8(-128)},
+		{name: "uint8 overflow", field: &u8, number: 256, err: ErrNumberOverflow, clamped: uint8(255)},
+		{name: "uint8 negative", field: &u8, number: -1, err: ErrNumberOverflow, clamped: uint8(0)},
+		{name: "int32 overflow", field: &i32, number: int64(math.MaxInt32) + 1, err: ErrNumberOverflow, clamped: int32(math.MaxInt32)},
+		{name: "uint64 max", field: &u64, number: uint64(math.MaxUint64), clamped: uint64(math.MaxUint64)},
+		{name: "int64 from big uint64", field: &i64, number: uint64(math.MaxUint64), err: ErrNumberOverflow, clamped: int64(math.MaxInt64)},
+	}
+	for _, test := range tests {
+		f := reflect.ValueOf(test.field).Elem()
+		err := SetNumber(f, test.number)
+		assert.Equal(t, test.err, err, test.name)
+
+		assert.NoError(t, setNumber(f, test.number, true), test.name)
+		assert.Equal(t, test.clamped, f.Interface(), test.name)
+	}
+}
+
+type narrowResponse struct {
+	Total    int8
+	PageNum  int8
+	PageSize int8
+	LastPage uint8
+}
+
+func TestPage_FillResponseOverflow(t *testing.T) {
+	page, err := Parse(&testRequest{PageNum: 2, PageSize: 10})
+	assert.NoError(t, err)
+	page.SetTotal(5000)
+	resp := &narrowResponse{}
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, narrowResponse{Total: 127, PageNum: 2, PageSize: 10, LastPage: 255}, *resp)
+
+	page, err = Parse(&testRequest{PageNum: 2, PageSize: 10}, WithOverflowPolicy(OverflowError))
+	assert.NoError(t, err)
+	page.SetTotal(5000)
+	assert.Equal(t, ErrNumberOverflow, page.FillResponse(resp))
+}
+
+func TestSetNumber_Kinds(t *testing.T) {
+	var (
+		ptr       *int64
+		ptrPtr    **int32
+		nullInt   sql.NullInt64
+		nullInt16 sql.NullInt16
+		nullFloat sql.NullFloat64
+		nullStr   sql.NullString
+		null      sql.Null[uint32]
+		wrapper   *wrapperspb.Int64Value
+		uwrapper  = &wrapperspb.UInt32Value{Value: 1}
+		swrapper  *wrapperspb.StringValue
+		f64       float64
+		f32       float32
+		str       string
+		big       string
+	)
+	tests := []struct {
+		name     string
+		field    interface{}
+		number   interface{}
+		excepted interface{}
+	}{
+		{name: "nil pointer", field: &ptr, number: 42, excepted: proto.Int64(42)},
+		{name: "nil pointer to pointer", field: &ptrPtr, number: 42, excepted: func() **int32 { p := proto.Int32(42); return &p }()},
+		{name: "sql.NullInt64", field: &nullInt, number: 42, excepted: sql.NullInt64{Int64: 42, Valid: true}},
+		{name: "sql.NullInt16", field: &nullInt16, number: 42, excepted: sql.NullInt16{Int16: 42, Valid: true}},
+		{name: "sql.NullFloat64", field: &nullFloat, number: 42, excepted: sql.NullFloat64{Float64: 42, Valid: true}},
+		{name: "sql.NullString", field: &nullStr, number: 42, excepted: sql.NullString{String: "42", Valid: true}},
+		{name: "sql.Null", field: &null, number: 42, excepted: sql.Null[uint32]{V: 42, Valid: true}},
+		{name: "float64", field: &f64, number: 42, excepted: float64(42)},
+		{name: "float32", field: &f32, number: int64(1) << 40, excepted: float32(1 << 40)},
+		{name: "string", field: &str, number: -42, excepted: "-42"},
+		{name: "string from uint64", field: &big, number: uint64(math.MaxUint64), excepted: "18446744073709551615"},
+	}
+	for _, test := range tests {
+		f := reflect.ValueOf(test.field).Elem()
+		assert.NoError(t, SetNumber(f, test.number), test.name)
+		assert.Equal(t, test.excepted, f.Interface(), test.name)
+	}
+
+	assert.NoError(t, SetNumber(reflect.ValueOf(&wrapper).Elem(), 42))
+	assert.True(t, proto.Equal(wrapperspb.Int64(42), wrapper))
+	assert.NoError(t, SetNumber(reflect.ValueOf(&uwrapper).Elem(), 42))
+	assert.True(t, proto.Equal(wrapperspb.UInt32(42), uwrapper))
+	assert.NoError(t, SetNumber(reflect.ValueOf(&swrapper).Elem(), 42))
+	assert.True(t, proto.Equal(wrapperspb.String("42"), swrapper))
+
+	// out of range for the wrapped type
+	var nullByte sql.NullByte
+	assert.Equal(t, ErrNumberOverflow, SetNumber(reflect.ValueOf(&nullByte).Elem(), 256))
+	assert.False(t, nullByte.Valid)
+	var i8 *int8
+	assert.Equal(t, ErrNumberOverflow, SetNumber(reflect.ValueOf(&i8).Elem(), 128))
+	assert.Nil(t, i8)
+
+	var other struct{ A, B int }
+	assert.Equal(t, ErrResponseFieldType, SetNumber(reflect.ValueOf(&other).Elem(), 1))
+	var nullTime sql.NullTime
+	assert.Equal(t, ErrResponseFieldType, SetNumber(reflect.ValueOf(&nullTime).Elem(), 1))
+}
+
+type wrappedResponse struct {
+	Total    *wrapperspb.Int64Value
+	PageNum  *int64
+	PageSize string
+	LastPage sql.NullInt32
+}
+
+func TestPage_FillWrappedResponse(t *testing.T) {
+	page, err := Parse(&testRequest{PageNum: 2, PageSize: 10})
+	assert.NoError(t, err)
+	page.SetTotal(25)
+	resp := &wrappedResponse{}
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, int64(25), resp.Total.GetValue())
+	assert.Equal(t, int64(2), *resp.PageNum)
+	assert.Equal(t, "10", resp.PageSize)
+	assert.Equal(t, sql.NullInt32{Int32: 3, Valid: true}, resp.LastPage)
+}
+
+func TestClamp(t *testing.T) {
+	assert.Equal(t, int32(math.MaxInt32), Clamp[int32](1<<40))
+	assert.Equal(t, int32(math.MinInt32), Clamp[int32](-1<<40))
+	assert.Equal(t, int32(7), Clamp[int32](7))
+	assert.Equal(t, uint32(math.MaxUint32), Clamp[uint32](1<<40))
+	assert.Equal(t, uint32(0), Clamp[uint32](-1))
+	assert.Equal(t, uint64(0), Clamp[uint64](-1))
+	assert.Equal(t, uint64(1<<40), Clamp[uint64](1<<40))
+	assert.Equal(t, int64(-1<<40), Clamp[int64](-1<<40))
+}
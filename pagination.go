@@ -1,9 +1,14 @@
 package pagination
 
 import (
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -12,14 +17,28 @@ var (
 	ErrInvalidOrderBy         = errors.New("invalid order")
 	ErrInvalidSearchKey       = errors.New("invalid search key")
 	ErrInvalidIsDescending    = errors.New("invalid is descending")
+	ErrInvalidPageToken       = errors.New("invalid page token")
+	ErrInvalidFilter          = errors.New("invalid filter")
+	ErrInvalidShowTotal       = errors.New("invalid show total")
 	ErrInvalidParseData       = errors.New("invalid data type parsing")
 	ErrInvalidResponse        = errors.New("invalid response")
 	ErrResponseFieldType      = errors.New("response filed type")
 	ErrResponseFieldUnsetable = errors.New("response field unsetable")
 	ErrTryToSetinvalidNumber  = errors.New("try to set invalid number to field")
+	ErrOffsetOverflow         = errors.New("offset overflow")
+	ErrNumberOverflow         = errors.New("number overflows field")
 )
 
-var _defaultResponseSearchingField = []string{"Page", "Pagination"}
+var (
+	_protoMessageType              = reflect.TypeOf((*proto.Message)(nil)).Elem()
+	_defaultResponseSearchingField = []string{"Page", "Pagination"}
+	// _responseFields are the fields FillResponse knows how to fill.
+	_responseFields = map[string]bool{
+		"Total": true, "PageNum": true, "CurrentPage": true, "CurrentPageNum": true, "Num": true,
+		"TotalIsCapped": true, "TotalCapped": true, "TotalIsEstimate": true, "IsEstimate": true,
+		"HasNext": true, "LastPage": true, "TotalPages": true, "NextPageToken": true, "NextToken": true, "PageSize": true, "Size": true,
+	}
+)
 
 type Page struct {
 	Num          int
@@ -27,35 +46,149 @@ type Page struct {
 	OrderBy      string
 	IsDescending bool
 	Query        string
-	Total        int
-	defaultSize  int
+	// Filter is a filter expression of the request, ShowTotal asks for the
+	// total to be counted.
+	Filter    string
+	ShowTotal bool
+	// Token is the page token of a request, NextToken the token of the page after this one.
+	Token     string
+	NextToken string
+	Total     int
+	// TotalIsCapped and TotalIsEstimate are set by Count, see Counter.
+	TotalIsCapped   bool
+	TotalIsEstimate bool
+	defaultSize     int
+	probe           bool
+	totalSet        bool
+	hasNext         bool
+	hasNextSet      bool
+	overflow        OverflowPolicy
+	allocate        bool
+	allocateSet     bool
+	keyNaming       KeyNaming
 }
 
+// Offset returns the offset of the page, clamped to the int32 range.
 func (p Page) Offset() int32 {
-	if p.Num <= 0 {
+	return clampInt32(p.Offset64())
+}
+
+// Offset64 returns the offset of the page, clamped to 0 and math.MaxInt64.
+// Negative sizes never give a negative offset.
+func (p Page) Offset64() int64 {
+	offset, ok := p.offset64()
+	if !ok {
+		if p.Size < 0 {
+			return 0
+		}
+		return math.MaxInt64
+	}
+	if offset < 0 {
 		return 0
 	}
-	return int32((p.Num - 1) * p.Size)
+	return offset
 }
 
+func (p Page) offset64() (int64, bool) {
+	if p.Num <= 0 {
+		return 0, true
+	}
+	return mulInt64(int64(p.Num)-1, int64(p.Size))
+}
+
+// Limit returns the limit of the page, clamped to the int32 range.
 func (p Page) Limit() int32 {
+	return clampInt32(p.Limit64())
+}
+
+// Limit64 returns the limit of the page, including the extra row of
+// WithProbe, clamped to the int64 range.
+func (p Page) Limit64() int64 {
+	if p.probe {
+		return p.probeLimit()
+	}
+	return p.limit()
+}
+
+// ProbeLimit returns the limit plus one extra row, which is used to find out
+// whether there is a next page without counting the rows.
+func (p Page) ProbeLimit() int32 {
+	return clampInt32(p.probeLimit())
+}
+
+func (p Page) probeLimit() int64 {
+	limit := p.limit()
+	if limit == 0 {
+		return 0
+	}
+	return addInt64(limit, 1)
+}
+
+func (p Page) limit() int64 {
 	if p.Size != 0 {
-		return int32(p.Size)
+		return int64(p.Size)
 	}
 
 	if p.Num != 0 {
-		return int32(p.defaultSize)
+		return int64(p.defaultSize)
 	}
 
 	return 0
 }
 
+// LastPage returns the number of the last page, 0 when the page has no size.
+func (p Page) LastPage() int {
+	if p.Size == 0 {
+		return 0
+	}
+	lastPage := p.Total / p.Size
+	if p.Total%p.Size != 0 {
+		lastPage++
+	}
+	return lastPage
+}
+
 func (p Page) Required() bool {
 	return p.Num > 0 && p.Size > 0
 }
 
 func (p *Page) SetTotal(total int) {
 	p.Total = total
+	p.totalSet = true
+}
+
+// Trim takes the number of rows fetched with ProbeLimit and reports whether
+// there are more rows after this page. When it returns true the caller should
+// drop the extra probe row, e.g. rows = rows[:len(rows)-1].
+func (p *Page) Trim(n int) (hasMore bool) {
+	limit := int(p.limit())
+	hasMore = limit > 0 && n > limit
+	p.SetHasNext(hasMore)
+	return hasMore
+}
+
+func (p *Page) SetHasNext(hasNext bool) {
+	p.hasNext = hasNext
+	p.hasNextSet = true
+}
+
+// HasNext reports whether there is a page after the current one. It uses the
+// result of Trim or SetHasNext when available, otherwise it is derived from Total.
+func (p Page) HasNext() bool {
+	if p.hasNextSet {
+		return p.hasNext
+	}
+	limit := int(p.limit())
+	if limit == 0 || !p.totalKnown() {
+		return false
+	}
+	return addInt64(p.Offset64(), int64(limit)) < int64(p.Total)
+}
+
+// totalKnown reports whether Total can be trusted. In probe mode the total is
+// unknown unless it was explicitly set.
+func (p Page) totalKnown() bool {
+	return !p.probe || p.totalSet
 }
 
 func (p Page) FillResponse(resp interface{}, fields ...string) error {
@@ -64,83 +197,225 @@ func (p Page) FillResponse(resp interface{}, fields ...string) error {
 	if len(fields) != 0 {
 		keywords = fields
 	}
+	v, err := responseStruct(resp)
+	if err != nil {
+		return err
+	}
+	if v, err = p.findContainer(v, keywords); err != nil {
+		return err
+	}
+	return p.fill(v)
+}
+
+// FillResponseAt fills the pagination block found at the dotted path of
+// field names, e.g. "Data.Users.Page". Numbers address elements of slices
+// and arrays. When the field at the path isn't a pagination block itself it
+// is searched like FillResponse does.
+func (p Page) FillResponseAt(resp interface{}, path string) error {
+	v, err := responseStruct(resp)
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		for _, name := range strings.Split(path, ".") {
+			if v, err = p.pathElem(v, name); err != nil {
+				return errors.Wrapf(err, "path %q", path)
+			}
+		}
+	}
+	if v, err = p.findContainer(v, _defaultResponseSearchingField); err != nil {
+		return errors.Wrapf(err, "path %q", path)
+	}
+	return p.fill(v)
+}
+
+// FillAll fills every pagination block of resp with the page of its path,
+// see FillResponseAt.
+func FillAll(resp interface{}, pages map[string]Page) error {
+	paths := make([]string, 0, len(pages))
+	for path := range pages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := pages[path].FillResponseAt(resp, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// responseStruct returns the struct or map resp points to.
+func responseStruct(resp interface{}) (reflect.Value, error) {
 	v := reflect.ValueOf(resp)
-	for v.Type().Kind() != reflect.Struct {
+	if !v.IsValid() {
+		return v, ErrInvalidResponse
+	}
+	for v.Type().Kind() != reflect.Struct && !isResponseMap(v.Type()) {
 		switch v.Type().Kind() {
 		case reflect.Ptr:
 			if v.Elem().IsValid() && !v.IsNil() {
 				v = v.Elem()
 				break
 			}
-			return ErrInvalidResponse
+			return v, ErrInvalidResponse
 		default:
-			return ErrInvalidResponse
+			return v, ErrInvalidResponse
+		}
+	}
+	return v, nil
+}
+
+// pathElem returns the field or element called name of v, dereferencing
+// pointers.
+func (p Page) pathElem(v reflect.Value, name string) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() && !p.allocates(v) {
+			return v, ErrInvalidResponse
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if f, ok := field(v, name, false); ok {
+			return f, nil
+		}
+	case reflect.Slice, reflect.Array:
+		if i, err := strconv.Atoi(name); err == nil && i >= 0 && i < v.Len() {
+			return v.Index(i), nil
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			if elem := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())); elem.IsValid() {
+				return elem, nil
+			}
 		}
 	}
+	return v, errors.Wrapf(ErrInvalidResponse, "no field %s", name)
+}
+
+// findContainer returns the struct holding the pagination fields, which is v
+// itself or a struct nested in one of the keyword fields.
+func (p Page) findContainer(v reflect.Value, keywords []string) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() && !p.allocates(v) {
+			return v, ErrInvalidResponse
+		}
+		v = v.Elem()
+	}
+	if isResponseMap(v.Type()) {
+		return p.mapContainer(v, keywords), nil
+	}
+	if v.Kind() != reflect.Struct {
+		return v, ErrInvalidResponse
+	}
 
 traverse:
 	for {
-		if v.FieldByName("Total").IsValid() &&
-			(v.FieldByName("PageNum").IsValid() || v.FieldByName("Num").IsValid() || v.FieldByName("CurrentPage").IsValid() || v.FieldByName("CurrentPageNum").IsValid()) &&
-			v.FieldByName("PageSize").IsValid() || v.FieldByName("Size").IsValid() {
+		t := v.Type()
+		if (hasField(t, "Total") || hasField(t, "HasNext")) &&
+			(hasField(t, "PageNum") || hasField(t, "Num") || hasField(t, "CurrentPage") || hasField(t, "CurrentPageNum")) &&
+			hasField(t, "PageSize") || hasField(t, "Size") {
 			break
 		}
 
 		for _, word := range keywords {
-			if v.FieldByName(word).IsValid() {
-				v = v.FieldByName(word)
+			if f, ok := field(v, word, false); ok {
+				v = f
 				for v.Type().Kind() == reflect.Ptr {
-					if !v.Elem().IsValid() {
-						return ErrInvalidResponse
+					if v.IsNil() && !p.allocates(v) {
+						return v, ErrInvalidResponse
 					}
 					v = v.Elem()
 				}
+				if v.Kind() != reflect.Struct {
+					return v, ErrInvalidResponse
+				}
 				goto traverse
 			}
 		}
-		return ErrInvalidResponse
+		return v, ErrInvalidResponse
+	}
+	return v, nil
+}
+
+// allocates allocates the nil pointer v when the page is allowed to, see
+// WithAllocate. Pointers which can't be set, such as unexported fields, are
+// never allocated.
+func (p Page) allocates(v reflect.Value) bool {
+	if v.Kind() != reflect.Ptr || !v.CanSet() {
+		return false
+	}
+	if p.allocateSet && !p.allocate {
+		return false
 	}
+	if !p.allocateSet && !v.Type().Implements(_protoMessageType) {
+		return false
+	}
+	v.Set(reflect.New(v.Type().Elem()))
+	return true
+}
 
-	for i := 0; i < v.NumField(); i++ {
-		if !v.Field(i).CanInterface() {
+// fill fills the pagination fields of the struct or map v.
+func (p Page) fill(v reflect.Value) error {
+	if v.Kind() == reflect.Map {
+		return p.fillMap(v)
+	}
+	for _, name := range fieldNames(v.Type()) {
+		if !_responseFields[name] {
+			continue
+		}
+		f, ok := field(v, name, true)
+		if !ok || !f.CanInterface() {
 			continue
 		}
-		f := v.Field(i)
-		switch v.Type().Field(i).Name {
+		switch name {
 		case "Total":
-			if err := SetNumber(f, p.Total); err != nil {
+			if !p.totalKnown() {
+				continue
+			}
+			if err := p.setNumber(f, p.Total); err != nil {
 				return err
 			}
 		case "PageNum", "CurrentPage", "CurrentPageNum", "Num":
-			if err := SetNumber(f, p.Num); err != nil {
+			if err := p.setNumber(f, p.Num); err != nil {
 				return err
 			}
-		case "LastPage":
-			if p.Size == 0 {
-				if err := SetNumber(f, 0); err != nil {
-					return err
-				}
-				continue
+		case "TotalIsCapped", "TotalCapped":
+			if err := SetBool(f, p.TotalIsCapped); err != nil {
+				return err
 			}
-			lastPage := p.Total / p.Size
-			if p.Total%p.Size == 0 {
-				if err := SetNumber(f, lastPage); err != nil {
-					return err
-				}
+		case "TotalIsEstimate", "IsEstimate":
+			if err := SetBool(f, p.TotalIsEstimate); err != nil {
+				return err
+			}
+		case "HasNext":
+			if err := SetBool(f, p.HasNext()); err != nil {
+				return err
+			}
+		case "LastPage", "TotalPages":
+			if !p.totalKnown() {
 				continue
 			}
-			if err := SetNumber(f, lastPage+1); err != nil {
+			if err := p.setNumber(f, p.LastPage()); err != nil {
+				return err
+			}
+		case "NextPageToken", "NextToken":
+			if err := SetString(f, p.NextToken); err != nil {
 				return err
 			}
-
 		case "PageSize", "Size":
 			if p.Size == 0 {
-				if err := SetNumber(f, p.Total); err != nil {
+				// the size of an unlimited page is the total
+				if !p.totalKnown() {
+					continue
+				}
+				if err := p.setNumber(f, p.Total); err != nil {
 					return err
 				}
 				continue
 			}
-			if err := SetNumber(f, p.Size); err != nil {
+			if err := p.setNumber(f, p.Size); err != nil {
 				return err
 			}
 		}
@@ -149,33 +424,155 @@ traverse:
 	return nil
 }
 
+func SetString(f reflect.Value, s string) error {
+	if !f.CanSet() {
+		return ErrResponseFieldUnsetable
+	}
+	if f.Kind() != reflect.String {
+		return ErrResponseFieldType
+	}
+	f.SetString(s)
+	return nil
+}
+
+func SetBool(f reflect.Value, b bool) error {
+	if !f.CanSet() {
+		return ErrResponseFieldUnsetable
+	}
+	if f.Kind() != reflect.Bool {
+		return ErrResponseFieldType
+	}
+	f.SetBool(b)
+	return nil
+}
+
+// SetNumber sets a number to a field. Besides integer fields it supports
+// floats, strings (formatted in base 10), pointers (allocated when nil),
+// sql.Null* types and protobuf wrappers such as wrapperspb.Int64Value. It
+// returns ErrNumberOverflow when the field can't hold the number.
 func SetNumber(f reflect.Value, number interface{}) error {
-	iv := reflect.ValueOf(number)
+	return setNumber(f, number, false)
+}
+
+// setNumber sets a number to a field following the overflow policy of the page.
+func (p Page) setNumber(f reflect.Value, number interface{}) error {
+	return setNumber(f, number, p.overflow == OverflowClamp)
+}
+
+func setNumber(f reflect.Value, number interface{}, clamp bool) error {
 	if !f.CanSet() {
 		return ErrResponseFieldUnsetable
 	}
-	if f.CanInt() {
-		if iv.CanInt() {
-			f.SetInt(reflect.ValueOf(number).Int())
-			return nil
+	v := reflect.ValueOf(number)
+
+	switch f.Kind() {
+	case reflect.Ptr:
+		if !f.IsNil() {
+			return setNumber(f.Elem(), number, clamp)
 		}
-		if iv.CanConvert(reflect.TypeOf(int(0))) {
-			f.SetInt(iv.Convert(reflect.TypeOf(int(0))).Int())
+		elem := reflect.New(f.Type().Elem())
+		if err := setNumber(elem.Elem(), number, clamp); err != nil {
+			return err
+		}
+		f.Set(elem)
+		return nil
+	case reflect.Struct:
+		value, valid, ok := wrappedValue(f)
+		if !ok {
+			return ErrResponseFieldType
+		}
+		if err := setNumber(value, number, clamp); err != nil {
+			return err
+		}
+		if valid.IsValid() {
+			valid.SetBool(true)
+		}
+		return nil
+	case reflect.String:
+		if v.CanUint() {
+			f.SetString(strconv.FormatUint(v.Uint(), 10))
 			return nil
 		}
+		n, ok, _ := toInt64(v)
+		if !ok {
+			return ErrTryToSetinvalidNumber
+		}
+		f.SetString(strconv.FormatInt(n, 10))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		x, ok := toFloat64(v)
+		if !ok {
+			return ErrTryToSetinvalidNumber
+		}
+		if f.OverflowFloat(x) {
+			if !clamp {
+				return ErrNumberOverflow
+			}
+			x = math.Copysign(math.MaxFloat32, x)
+		}
+		f.SetFloat(x)
+		return nil
+	}
+
+	switch {
+	case f.CanUint() && v.CanUint():
+		u := v.Uint()
+		if f.OverflowUint(u) {
+			if !clamp {
+				return ErrNumberOverflow
+			}
+			u = uint64(1)<<f.Type().Bits() - 1
+		}
+		f.SetUint(u)
+		return nil
+	case f.CanInt(), f.CanUint():
+	default:
+		return ErrResponseFieldType
+	}
+
+	n, ok, overflow := toInt64(v)
+	if !ok {
 		return ErrTryToSetinvalidNumber
 	}
-	if f.CanUint() {
-		if iv.CanUint() {
-			f.SetUint(iv.Uint())
-			return nil
+	if f.CanInt() {
+		if overflow || f.OverflowInt(n) {
+			if !clamp {
+				return ErrNumberOverflow
+			}
+			n = clampInt(f, n)
 		}
-		if iv.CanConvert(reflect.TypeOf(uint(0))) {
-			f.SetUint(iv.Convert(reflect.TypeOf(uint(0))).Uint())
-			return nil
+		f.SetInt(n)
+		return nil
+	}
+	if n < 0 || f.OverflowUint(uint64(n)) {
+		if !clamp {
+			return ErrNumberOverflow
 		}
-		return ErrTryToSetinvalidNumber
+		f.SetUint(clampUint(f, n))
+		return nil
 	}
+	f.SetUint(uint64(n))
+	return nil
+}
 
-	return ErrResponseFieldType
+// wrappedValue returns the value field of a wrapper struct: a protobuf
+// wrapper message such as wrapperspb.Int64Value, or a database/sql nullable
+// type such as sql.NullInt64 whose Valid field is returned as well.
+func wrappedValue(f reflect.Value) (value, valid reflect.Value, ok bool) {
+	t := f.Type()
+	if t.PkgPath() == "database/sql" && t.NumField() == 2 {
+		if t.Field(1).Name == "Valid" {
+			return f.Field(0), f.Field(1), true
+		}
+		return reflect.Value{}, reflect.Value{}, false
+	}
+	if !f.CanAddr() {
+		return reflect.Value{}, reflect.Value{}, false
+	}
+	if _, isMessage := f.Addr().Interface().(proto.Message); isMessage {
+		if value := f.FieldByName("Value"); value.IsValid() {
+			return value, reflect.Value{}, true
+		}
+	}
+	return reflect.Value{}, reflect.Value{}, false
 }
@@ -9,6 +9,7 @@ package pagination
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -20,6 +21,175 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// PageField marks a field of a request message as one of the pagination
+// fields, e.g. int32 limit = 1 [(pagination.field) = PAGE_SIZE].
+type PageField int32
+
+const (
+	PageField_PAGE_FIELD_UNSPECIFIED PageField = 0
+	PageField_PAGE_NUM               PageField = 1
+	PageField_PAGE_SIZE              PageField = 2
+	PageField_ORDER_BY               PageField = 3
+	PageField_IS_DESCENDING          PageField = 4
+	PageField_QUERY                  PageField = 5
+	PageField_PAGE_TOKEN             PageField = 6
+	PageField_FILTER                 PageField = 7
+	PageField_SHOW_TOTAL             PageField = 8
+)
+
+// Enum value maps for PageField.
+var (
+	PageField_name = map[int32]string{
+		0: "PAGE_FIELD_UNSPECIFIED",
+		1: "PAGE_NUM",
+		2: "PAGE_SIZE",
+		3: "ORDER_BY",
+		4: "IS_DESCENDING",
+		5: "QUERY",
+		6: "PAGE_TOKEN",
+		7: "FILTER",
+		8: "SHOW_TOTAL",
+	}
+	PageField_value = map[string]int32{
+		"PAGE_FIELD_UNSPECIFIED": 0,
+		"PAGE_NUM":               1,
+		"PAGE_SIZE":              2,
+		"ORDER_BY":               3,
+		"IS_DESCENDING":          4,
+		"QUERY":                  5,
+		"PAGE_TOKEN":             6,
+		"FILTER":                 7,
+		"SHOW_TOTAL":             8,
+	}
+)
+
+func (x PageField) Enum() *PageField {
+	p := new(PageField)
+	*p = x
+	return p
+}
+
+func (x PageField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PageField) Descriptor() protoreflect.EnumDescriptor {
+	return file_pagination_proto_enumTypes[0].Descriptor()
+}
+
+func (PageField) Type() protoreflect.EnumType {
+	return &file_pagination_proto_enumTypes[0]
+}
+
+func (x PageField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PageField.Descriptor instead.
+func (PageField) EnumDescriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{0}
+}
+
+type SortField_Direction int32
+
+const (
+	// DIRECTION_UNSPECIFIED follows is_descending of the request.
+	SortField_DIRECTION_UNSPECIFIED SortField_Direction = 0
+	SortField_ASC                   SortField_Direction = 1
+	SortField_DESC                  SortField_Direction = 2
+)
+
+// Enum value maps for SortField_Direction.
+var (
+	SortField_Direction_name = map[int32]string{
+		0: "DIRECTION_UNSPECIFIED",
+		1: "ASC",
+		2: "DESC",
+	}
+	SortField_Direction_value = map[string]int32{
+		"DIRECTION_UNSPECIFIED": 0,
+		"ASC":                   1,
+		"DESC":                  2,
+	}
+)
+
+func (x SortField_Direction) Enum() *SortField_Direction {
+	p := new(SortField_Direction)
+	*p = x
+	return p
+}
+
+func (x SortField_Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortField_Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_pagination_proto_enumTypes[1].Descriptor()
+}
+
+func (SortField_Direction) Type() protoreflect.EnumType {
+	return &file_pagination_proto_enumTypes[1]
+}
+
+func (x SortField_Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortField_Direction.Descriptor instead.
+func (SortField_Direction) EnumDescriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{2, 0}
+}
+
+type SortField_Nulls int32
+
+const (
+	// NULLS_UNSPECIFIED leaves the position of NULL values to the database.
+	SortField_NULLS_UNSPECIFIED SortField_Nulls = 0
+	SortField_NULLS_FIRST       SortField_Nulls = 1
+	SortField_NULLS_LAST        SortField_Nulls = 2
+)
+
+// Enum value maps for SortField_Nulls.
+var (
+	SortField_Nulls_name = map[int32]string{
+		0: "NULLS_UNSPECIFIED",
+		1: "NULLS_FIRST",
+		2: "NULLS_LAST",
+	}
+	SortField_Nulls_value = map[string]int32{
+		"NULLS_UNSPECIFIED": 0,
+		"NULLS_FIRST":       1,
+		"NULLS_LAST":        2,
+	}
+)
+
+func (x SortField_Nulls) Enum() *SortField_Nulls {
+	p := new(SortField_Nulls)
+	*p = x
+	return p
+}
+
+func (x SortField_Nulls) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortField_Nulls) Descriptor() protoreflect.EnumDescriptor {
+	return file_pagination_proto_enumTypes[2].Descriptor()
+}
+
+func (SortField_Nulls) Type() protoreflect.EnumType {
+	return &file_pagination_proto_enumTypes[2]
+}
+
+func (x SortField_Nulls) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortField_Nulls.Descriptor instead.
+func (SortField_Nulls) EnumDescriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{2, 1}
+}
+
 type PaginationRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -30,6 +200,12 @@ type PaginationRequest struct {
 	OrderBy      string `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
 	IsDescending bool   `protobuf:"varint,4,opt,name=is_descending,json=isDescending,proto3" json:"is_descending,omitempty"`
 	Query        string `protobuf:"bytes,5,opt,name=query,proto3" json:"query,omitempty"`
+	// sort takes precedence over order_by when set.
+	Sort      []*SortField `protobuf:"bytes,6,rep,name=sort,proto3" json:"sort,omitempty"`
+	Filter    string       `protobuf:"bytes,7,opt,name=filter,proto3" json:"filter,omitempty"`
+	PageToken string       `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// show_total asks for the total to be counted.
+	ShowTotal bool `protobuf:"varint,9,opt,name=show_total,json=showTotal,proto3" json:"show_total,omitempty"`
 }
 
 func (x *PaginationRequest) Reset() {
@@ -99,15 +275,47 @@ func (x *PaginationRequest) GetQuery() string {
 	return ""
 }
 
+func (x *PaginationRequest) GetSort() []*SortField {
+	if x != nil {
+		return x.Sort
+	}
+	return nil
+}
+
+func (x *PaginationRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *PaginationRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *PaginationRequest) GetShowTotal() bool {
+	if x != nil {
+		return x.ShowTotal
+	}
+	return false
+}
+
 type PaginationResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Total    int64 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
-	PageNum  int64 `protobuf:"varint,2,opt,name=page_num,json=pageNum,proto3" json:"page_num,omitempty"`
-	LastPage int64 `protobuf:"varint,3,opt,name=last_page,json=lastPage,proto3" json:"last_page,omitempty"`
-	PageSize int64 `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Total           int64  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	PageNum         int64  `protobuf:"varint,2,opt,name=page_num,json=pageNum,proto3" json:"page_num,omitempty"`
+	LastPage        int64  `protobuf:"varint,3,opt,name=last_page,json=lastPage,proto3" json:"last_page,omitempty"`
+	PageSize        int64  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	NextPageToken   string `protobuf:"bytes,5,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	HasNext         bool   `protobuf:"varint,6,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
+	TotalPages      int64  `protobuf:"varint,7,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	TotalIsEstimate bool   `protobuf:"varint,8,opt,name=total_is_estimate,json=totalIsEstimate,proto3" json:"total_is_estimate,omitempty"`
 }
 
 func (x *PaginationResponse) Reset() {
@@ -170,33 +378,287 @@ func (x *PaginationResponse) GetPageSize() int64 {
 	return 0
 }
 
+func (x *PaginationResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *PaginationResponse) GetHasNext() bool {
+	if x != nil {
+		return x.HasNext
+	}
+	return false
+}
+
+func (x *PaginationResponse) GetTotalPages() int64 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *PaginationResponse) GetTotalIsEstimate() bool {
+	if x != nil {
+		return x.TotalIsEstimate
+	}
+	return false
+}
+
+// SortField is one field of a multi-field sort.
+type SortField struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field     string              `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Direction SortField_Direction `protobuf:"varint,2,opt,name=direction,proto3,enum=pagination.SortField_Direction" json:"direction,omitempty"`
+	Nulls     SortField_Nulls     `protobuf:"varint,3,opt,name=nulls,proto3,enum=pagination.SortField_Nulls" json:"nulls,omitempty"`
+}
+
+func (x *SortField) Reset() {
+	*x = SortField{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pagination_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SortField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SortField) ProtoMessage() {}
+
+func (x *SortField) ProtoReflect() protoreflect.Message {
+	mi := &file_pagination_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SortField.ProtoReflect.Descriptor instead.
+func (*SortField) Descriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SortField) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *SortField) GetDirection() SortField_Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return SortField_DIRECTION_UNSPECIFIED
+}
+
+func (x *SortField) GetNulls() SortField_Nulls {
+	if x != nil {
+		return x.Nulls
+	}
+	return SortField_NULLS_UNSPECIFIED
+}
+
+// PagePolicy limits the pages of a request message, e.g.
+// option (pagination.policy) = {default_size: 20, max_size: 100, sortable: ["name"]}.
+type PagePolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// default_size is the page size used when only a page number is given.
+	DefaultSize int64 `protobuf:"varint,1,opt,name=default_size,json=defaultSize,proto3" json:"default_size,omitempty"`
+	// max_size caps the page size, larger sizes are reduced to it.
+	MaxSize int64 `protobuf:"varint,2,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	// sortable lists the fields the request may be ordered by, any field when empty.
+	Sortable []string `protobuf:"bytes,3,rep,name=sortable,proto3" json:"sortable,omitempty"`
+}
+
+func (x *PagePolicy) Reset() {
+	*x = PagePolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pagination_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PagePolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PagePolicy) ProtoMessage() {}
+
+func (x *PagePolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_pagination_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PagePolicy.ProtoReflect.Descriptor instead.
+func (*PagePolicy) Descriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PagePolicy) GetDefaultSize() int64 {
+	if x != nil {
+		return x.DefaultSize
+	}
+	return 0
+}
+
+func (x *PagePolicy) GetMaxSize() int64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *PagePolicy) GetSortable() []string {
+	if x != nil {
+		return x.Sortable
+	}
+	return nil
+}
+
+var file_pagination_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*PageField)(nil),
+		Field:         51000,
+		Name:          "pagination.field",
+		Tag:           "varint,51000,opt,name=field,enum=pagination.PageField",
+		Filename:      "pagination.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*PagePolicy)(nil),
+		Field:         51001,
+		Name:          "pagination.policy",
+		Tag:           "bytes,51001,opt,name=policy",
+		Filename:      "pagination.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional pagination.PageField field = 51000;
+	E_Field = &file_pagination_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// optional pagination.PagePolicy policy = 51001;
+	E_Policy = &file_pagination_proto_extTypes[1]
+)
+
 var File_pagination_proto protoreflect.FileDescriptor
 
 var file_pagination_proto_rawDesc = []byte{
 	0x0a, 0x10, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xa1,
-	0x01, 0x0a, 0x11, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x6e, 0x75, 0x6d,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x70, 0x61, 0x67, 0x65, 0x4e, 0x75, 0x6d, 0x12,
-	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x19, 0x0a, 0x08,
-	0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x62, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x73, 0x5f, 0x64, 0x65,
-	0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c,
-	0x69, 0x73, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05,
-	0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65,
-	0x72, 0x79, 0x22, 0x7f, 0x0a, 0x12, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61,
-	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x19,
-	0x0a, 0x08, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x07, 0x70, 0x61, 0x67, 0x65, 0x4e, 0x75, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73,
-	0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61,
-	0x73, 0x74, 0x50, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73,
-	0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53,
-	0x69, 0x7a, 0x65, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x75, 0x70, 0x74, 0x75, 0x74, 0x75, 0x2f, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x2d, 0x67, 0x6f, 0x2f, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x6f, 0x12, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x20,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xa2, 0x02, 0x0a, 0x11, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x6e,
+	0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x70, 0x61, 0x67, 0x65, 0x4e, 0x75,
+	0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x19,
+	0x0a, 0x08, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x62, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x73, 0x5f,
+	0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0c, 0x69, 0x73, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x14,
+	0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71,
+	0x75, 0x65, 0x72, 0x79, 0x12, 0x29, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x53, 0x6f, 0x72, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67,
+	0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x68, 0x6f, 0x77, 0x5f, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x68, 0x6f, 0x77,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x8f, 0x02, 0x0a, 0x12, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x70, 0x61, 0x67, 0x65, 0x4e, 0x75, 0x6d, 0x12, 0x1b, 0x0a,
+	0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x50, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61,
+	0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70,
+	0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f,
+	0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x19, 0x0a, 0x08, 0x68, 0x61, 0x73, 0x5f, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x68, 0x61, 0x73, 0x4e, 0x65, 0x78, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x61, 0x67, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x69, 0x73, 0x5f, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x49, 0x73, 0x45,
+	0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x22, 0x8f, 0x02, 0x0a, 0x09, 0x53, 0x6f, 0x72, 0x74,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x3d, 0x0a, 0x09, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f,
+	0x2e, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x6f, 0x72, 0x74,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x05, 0x6e, 0x75,
+	0x6c, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x70, 0x61, 0x67, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x6f, 0x72, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x2e, 0x4e, 0x75, 0x6c, 0x6c, 0x73, 0x52, 0x05, 0x6e, 0x75, 0x6c, 0x6c, 0x73, 0x22, 0x39, 0x0a,
+	0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x15, 0x44, 0x49,
+	0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x53, 0x43, 0x10, 0x01, 0x12, 0x08,
+	0x0a, 0x04, 0x44, 0x45, 0x53, 0x43, 0x10, 0x02, 0x22, 0x3f, 0x0a, 0x05, 0x4e, 0x75, 0x6c, 0x6c,
+	0x73, 0x12, 0x15, 0x0a, 0x11, 0x4e, 0x55, 0x4c, 0x4c, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4e, 0x55, 0x4c, 0x4c,
+	0x53, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x4e, 0x55, 0x4c,
+	0x4c, 0x53, 0x5f, 0x4c, 0x41, 0x53, 0x54, 0x10, 0x02, 0x22, 0x66, 0x0a, 0x0a, 0x50, 0x61, 0x67,
+	0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x64,
+	0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61,
+	0x78, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6d, 0x61,
+	0x78, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6f, 0x72, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6f, 0x72, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x2a, 0x9c, 0x01, 0x0a, 0x09, 0x50, 0x61, 0x67, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12,
+	0x1a, 0x0a, 0x16, 0x50, 0x41, 0x47, 0x45, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x50,
+	0x41, 0x47, 0x45, 0x5f, 0x4e, 0x55, 0x4d, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x50, 0x41, 0x47,
+	0x45, 0x5f, 0x53, 0x49, 0x5a, 0x45, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x52, 0x44, 0x45,
+	0x52, 0x5f, 0x42, 0x59, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x53, 0x5f, 0x44, 0x45, 0x53,
+	0x43, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x51, 0x55, 0x45,
+	0x52, 0x59, 0x10, 0x05, 0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x4f, 0x4b,
+	0x45, 0x4e, 0x10, 0x06, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x49, 0x4c, 0x54, 0x45, 0x52, 0x10, 0x07,
+	0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x48, 0x4f, 0x57, 0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c, 0x10, 0x08,
+	0x3a, 0x4c, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb8, 0x8e, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x15, 0x2e, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x50, 0x61,
+	0x67, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x51,
+	0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb9, 0x8e, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x50,
+	0x61, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x75, 0x70, 0x74, 0x75, 0x74, 0x75, 0x2f, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2d, 0x67, 0x6f, 0x2f, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -211,17 +673,32 @@ func file_pagination_proto_rawDescGZIP() []byte {
 	return file_pagination_proto_rawDescData
 }
 
-var file_pagination_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pagination_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_pagination_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_pagination_proto_goTypes = []interface{}{
-	(*PaginationRequest)(nil),  // 0: pagination.PaginationRequest
-	(*PaginationResponse)(nil), // 1: pagination.PaginationResponse
+	(PageField)(0),                      // 0: pagination.PageField
+	(SortField_Direction)(0),            // 1: pagination.SortField.Direction
+	(SortField_Nulls)(0),                // 2: pagination.SortField.Nulls
+	(*PaginationRequest)(nil),           // 3: pagination.PaginationRequest
+	(*PaginationResponse)(nil),          // 4: pagination.PaginationResponse
+	(*SortField)(nil),                   // 5: pagination.SortField
+	(*PagePolicy)(nil),                  // 6: pagination.PagePolicy
+	(*descriptorpb.FieldOptions)(nil),   // 7: google.protobuf.FieldOptions
+	(*descriptorpb.MessageOptions)(nil), // 8: google.protobuf.MessageOptions
 }
 var file_pagination_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	5, // 0: pagination.PaginationRequest.sort:type_name -> pagination.SortField
+	1, // 1: pagination.SortField.direction:type_name -> pagination.SortField.Direction
+	2, // 2: pagination.SortField.nulls:type_name -> pagination.SortField.Nulls
+	7, // 3: pagination.field:extendee -> google.protobuf.FieldOptions
+	8, // 4: pagination.policy:extendee -> google.protobuf.MessageOptions
+	0, // 5: pagination.field:type_name -> pagination.PageField
+	6, // 6: pagination.policy:type_name -> pagination.PagePolicy
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	5, // [5:7] is the sub-list for extension type_name
+	3, // [3:5] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_pagination_proto_init() }
@@ -254,20 +731,46 @@ func file_pagination_proto_init() {
 				return nil
 			}
 		}
+		file_pagination_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SortField); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pagination_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PagePolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pagination_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
-			NumExtensions: 0,
+			NumEnums:      3,
+			NumMessages:   4,
+			NumExtensions: 2,
 			NumServices:   0,
 		},
 		GoTypes:           file_pagination_proto_goTypes,
 		DependencyIndexes: file_pagination_proto_depIdxs,
+		EnumInfos:         file_pagination_proto_enumTypes,
 		MessageInfos:      file_pagination_proto_msgTypes,
+		ExtensionInfos:    file_pagination_proto_extTypes,
 	}.Build()
 	File_pagination_proto = out.File
 	file_pagination_proto_rawDesc = nil
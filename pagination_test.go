@@ -1,10 +1,13 @@
 package pagination
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type testRequest struct {
@@ -43,7 +46,7 @@ var (
 			PageSize:     50,
 			OrderBy:      "id",
 			IsDescending: true,
-			SearchKey:    "search",
+			Query:        "search",
 		},
 	}
 	customData = testRequest{
@@ -177,7 +180,7 @@ func TestInvalidDataTypeParse(t *testing.T) {
 	}
 	for _, test := range tests {
 		_, err := Parse(test.data)
-		assert.Equal(t, test.excepted, err)
+		assert.ErrorIs(t, err, test.excepted, test.name)
 	}
 }
 
@@ -272,10 +275,10 @@ func TestPage_Required(t *testing.T) {
 }
 
 type ListResponse struct {
-	Total    int64  `json:"total"`
-	PageNum  int64  `json:"page_num"`
-	PageSize int64  `json:"page_size"`
-	data     string `json:"data"`
+	Total    int64 `json:"total"`
+	PageNum  int64 `json:"page_num"`
+	PageSize int64 `json:"page_size"`
+	data     string
 }
 
 func TestPage_FillResponse(t *testing.T) {
@@ -374,3 +377,316 @@ func TestSetNumber(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEqual(t, 0, oneInt64)
 }
+
+type probeResponse struct {
+	Total    int64
+	PageNum  int64
+	PageSize int64
+	LastPage int64
+	HasNext  bool
+}
+
+func TestPage_Probe(t *testing.T) {
+	page, err := Parse(&testRequest{PageNum: 2, PageSize: 10}, WithProbe())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(11), page.Limit())
+	assert.Equal(t, int32(11), page.ProbeLimit())
+	assert.Equal(t, int32(10), page.Offset())
+
+	assert.True(t, page.Trim(11))
+	resp := &probeResponse{Total: -1, LastPage: -1}
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, probeResponse{Total: -1, PageNum: 2, PageSize: 10, LastPage: -1, HasNext: true}, *resp)
+
+	assert.False(t, page.Trim(7))
+	assert.NoError(t, page.FillResponse(resp))
+	assert.False(t, resp.HasNext)
+
+	// total set explicitly is still filled
+	page.SetTotal(17)
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, int64(17), resp.Total)
+	assert.Equal(t, int64(2), resp.LastPage)
+
+	// without probe mode HasNext is derived from Total
+	page, err = Parse(&testRequest{PageNum: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), page.Limit())
+	page.SetTotal(11)
+	assert.True(t, page.HasNext())
+	page.SetTotal(10)
+	assert.False(t, page.HasNext())
+
+	// no limit never has a next page
+	page, err = Parse(&testRequest{}, WithProbe())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), page.Limit())
+	assert.False(t, page.Trim(100))
+
+	// the size of an unlimited page is unknown without a total
+	resp = &probeResponse{Total: -1, PageSize: -1, LastPage: -1}
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, probeResponse{Total: -1, PageSize: -1, LastPage: -1}, *resp)
+	page.SetTotal(100)
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, probeResponse{Total: 100, PageSize: 100}, *resp)
+}
+
+func TestParse_Token(t *testing.T) {
+	page, err := Parse(struct {
+		PageSize  int
+		PageToken string
+	}{PageSize: 10, PageToken: "abc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", page.Token)
+
+	_, err = Parse(struct {
+		PageSize  int
+		PageToken []byte
+	}{PageSize: 10})
+	assert.Equal(t, ErrInvalidPageToken, err)
+
+	// other tokens, e.g. for authentication, are not page tokens
+	page, err = Parse(struct {
+		PageSize int
+		Token    string
+	}{PageSize: 10, Token: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", page.Token)
+	_, err = Parse(struct {
+		PageSize int
+		Token    []byte
+	}{PageSize: 10})
+	assert.NoError(t, err)
+}
+
+func TestParse_FieldTypes(t *testing.T) {
+	num, size := int32(3), int64(20)
+	tests := []struct {
+		name     string
+		data     interface{}
+		num      int
+		size     int
+		excepted error
+	}{
+		{
+			name: "pointers",
+			data: struct {
+				PageNum  *int32
+				PageSize *int64
+				OrderBy  *string
+			}{PageNum: &num, PageSize: &size},
+			num:  3,
+			size: 20,
+		},
+		{
+			name: "nil pointers",
+			data: struct {
+				PageNum  *int32
+				PageSize *int64
+			}{},
+		},
+		{
+			name: "sql.Null",
+			data: struct {
+				PageNum  sql.NullInt64
+				PageSize sql.NullInt32
+			}{PageNum: sql.NullInt64{Int64: 3, Valid: true}, PageSize: sql.NullInt32{Int32: 20}},
+			num: 3,
+		},
+		{
+			name: "wrappers",
+			data: struct {
+				PageNum  *wrapperspb.Int32Value
+				PageSize *wrapperspb.UInt64Value
+			}{PageNum: wrapperspb.Int32(3), PageSize: wrapperspb.UInt64(20)},
+			num:  3,
+			size: 20,
+		},
+		{
+			name: "strings",
+			data: struct {
+				PageNum  string
+				PageSize *string
+			}{PageNum: " 3 ", PageSize: proto.String("20")},
+			num:  3,
+			size: 20,
+		},
+		{
+			name: "empty strings",
+			data: struct {
+				PageNum  string
+				PageSize string
+			}{},
+		},
+		{
+			name: "wrapped strings",
+			data: struct {
+				PageNum  sql.NullString
+				PageSize *wrapperspb.StringValue
+			}{PageNum: sql.NullString{String: "3", Valid: true}, PageSize: wrapperspb.String("20")},
+			num:  3,
+			size: 20,
+		},
+		{
+			name: "invalid page number",
+			data: struct {
+				PageNum  string
+				PageSize int
+			}{PageNum: "three"},
+			excepted: ErrInvalidPageNum,
+		},
+		{
+			name: "invalid page size",
+			data: struct {
+				PageNum  int
+				PageSize *wrapperspb.StringValue
+			}{PageSize: wrapperspb.String("1e3")},
+			excepted: ErrInvalidPageSize,
+		},
+		{
+			name: "invalid type",
+			data: struct {
+				PageNum  int
+				PageSize sql.NullTime
+			}{PageSize: sql.NullTime{Valid: true}},
+			excepted: ErrInvalidPageSize,
+		},
+	}
+	for _, test := range tests {
+		page, err := Parse(test.data)
+		assert.ErrorIs(t, err, test.excepted, test.name)
+		if test.excepted != nil {
+			continue
+		}
+		assert.Equal(t, test.num, page.Num, test.name)
+		assert.Equal(t, test.size, page.Size, test.name)
+	}
+
+	_, err := Parse(struct {
+		PageNum  string
+		PageSize int
+	}{PageNum: "three"})
+	assert.EqualError(t, err, `"three" is not a number: invalid page number`)
+	n, err := ParseInt(" 3 ", ErrInvalidPageSize)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	_, err = ParseInt("three", ErrInvalidPageSize)
+	assert.EqualError(t, err, `"three" is not a number: invalid page size`)
+
+	// nil values leave the default size in place
+	page, err := Parse(struct {
+		PageNum  *wrapperspb.Int32Value
+		PageSize *wrapperspb.Int32Value
+	}{PageNum: wrapperspb.Int32(2)})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(15), page.Limit())
+}
+
+type aggregateResponse struct {
+	Data struct {
+		Users  *SearchDialogCasesResponse
+		Groups *PaginationResponse
+	}
+	Sections []ListResponse
+	Missing  *ListResponse
+}
+
+func TestPage_FillResponseAt(t *testing.T) {
+	users := Page{Num: 1, Size: 10}
+	users.SetTotal(30)
+	groups := Page{Num: 2, Size: 5}
+	groups.SetTotal(7)
+	section := Page{Num: 3, Size: 20}
+	section.SetTotal(100)
+
+	resp := &aggregateResponse{Sections: make([]ListResponse, 2)}
+	resp.Data.Users = &SearchDialogCasesResponse{Page: &PaginationResponse{}}
+	resp.Data.Groups = &PaginationResponse{}
+
+	assert.NoError(t, users.FillResponseAt(resp, "Data.Users.Page"))
+	assert.Equal(t, int64(30), resp.Data.Users.Page.Total)
+	assert.Equal(t, int64(10), resp.Data.Users.Page.PageSize)
+	assert.Equal(t, int64(0), resp.Data.Groups.Total)
+
+	// the block is searched below the path like FillResponse does
+	assert.NoError(t, groups.FillResponseAt(resp, "Data.Users"))
+	assert.Equal(t, int64(7), resp.Data.Users.Page.Total)
+
+	assert.NoError(t, FillAll(resp, map[string]Page{
+		"Data.Users":  users,
+		"Data.Groups": groups,
+		"Sections.1":  section,
+	}))
+	assert.Equal(t, int64(30), resp.Data.Users.Page.Total)
+	assert.Equal(t, int64(2), resp.Data.Groups.PageNum)
+	assert.Equal(t, int64(7), resp.Data.Groups.Total)
+	assert.Equal(t, ListResponse{}, resp.Sections[0])
+	assert.Equal(t, ListResponse{Total: 100, PageNum: 3, PageSize: 20}, resp.Sections[1])
+
+	for _, path := range []string{"Data.Nope", "Sections.2", "Sections.x", "Missing", "Data"} {
+		assert.ErrorIs(t, users.FillResponseAt(resp, path), ErrInvalidResponse, path)
+	}
+	assert.ErrorIs(t, FillAll(resp, map[string]Page{"Data.Users": users, "Missing": users}), ErrInvalidResponse)
+}
+
+func TestPage_FillResponse_Allocate(t *testing.T) {
+	page := Page{Num: 2, Size: 10}
+	page.SetTotal(25)
+
+	// protobuf containers are allocated by default
+	pbResp := &SearchDialogCasesResponse{}
+	assert.NoError(t, page.FillResponse(pbResp))
+	assert.Equal(t, int64(25), pbResp.Page.Total)
+
+	resp := &struct{ Pagination *ListResponse }{}
+	assert.ErrorIs(t, page.FillResponse(resp), ErrInvalidResponse)
+	assert.Nil(t, resp.Pagination)
+
+	page, err := Parse(&testRequest{PageNum: 2, PageSize: 10}, WithAllocate(true))
+	assert.NoError(t, err)
+	page.SetTotal(25)
+	assert.NoError(t, page.FillResponse(resp))
+	assert.Equal(t, ListResponse{Total: 25, PageNum: 2, PageSize: 10}, *resp.Pagination)
+
+	// configured aliases and paths
+	aliasResp := &struct{ Meta **ListResponse }{}
+	assert.NoError(t, page.FillResponse(aliasResp, "Meta"))
+	assert.Equal(t, int64(25), (**aliasResp.Meta).Total)
+	nested := &struct {
+		Data *struct{ Users *ListResponse }
+	}{}
+	assert.NoError(t, page.FillResponseAt(nested, "Data.Users"))
+	assert.Equal(t, int64(25), nested.Data.Users.Total)
+
+	// never through unexported fields or values which can't be set
+	unexported := &struct{ Page *struct{ page *ListResponse } }{}
+	assert.ErrorIs(t, page.FillResponseAt(unexported, "Page.page"), ErrInvalidResponse)
+	assert.ErrorIs(t, page.FillResponse(struct{ Page *ListResponse }{}), ErrInvalidResponse)
+
+	page, err = Parse(&testRequest{}, WithAllocate(false))
+	assert.NoError(t, err)
+	pbResp = &SearchDialogCasesResponse{}
+	assert.ErrorIs(t, page.FillResponse(pbResp), ErrInvalidResponse)
+	assert.Nil(t, pbResp.Page)
+}
+
+func TestNew(t *testing.T) {
+	page, err := New(Page{Num: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(15), page.Limit())
+
+	page, err = New(Page{Num: 2, Size: 500}, WithMaxSize(100), WithDefaultSize(20))
+	assert.NoError(t, err)
+	assert.Equal(t, Page{Num: 2, Size: 100, defaultSize: 20}, page)
+
+	// the default size of a parsed page is kept
+	parsed, err := Parse(&testRequest{PageNum: 1}, WithDefaultSize(30))
+	assert.NoError(t, err)
+	page, err = New(parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(30), page.Limit())
+
+	_, err = New(Page{OrderBy: "id"}, WithSortable("name"))
+	assert.ErrorIs(t, err, ErrInvalidOrderBy)
+}
@@ -1,6 +1,12 @@
 package pagination
 
-import "reflect"
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
 
 type Option func(*Page) error
 
@@ -23,64 +29,207 @@ func Parse(req interface{}, options ...Option) (Page, error) {
 		}
 	}
 
-	if !((v.FieldByName("PageNum").IsValid() || v.FieldByName("Num").IsValid()) &&
-		(v.FieldByName("PageSize").IsValid() || v.FieldByName("Size").IsValid())) {
+	if t := v.Type(); !((hasField(t, "PageNum") || hasField(t, "Num")) &&
+		(hasField(t, "PageSize") || hasField(t, "Size"))) {
 		for _, word := range _requestStructSearchFields {
-			if v.FieldByName(word).IsValid() {
-				v = v.FieldByName(word)
+			if f, ok := field(v, word, false); ok {
+				v = f
 				for v.Type().Kind() == reflect.Ptr {
+					if v.IsNil() {
+						return q, q.apply(options)
+					}
 					v = v.Elem()
 				}
+				if v.Kind() != reflect.Struct {
+					return q, ErrInvalidParseData
+				}
 				break
 			}
 		}
 	}
 
-	for i := 0; i < v.NumField(); i++ {
-		if v.Field(i).CanInterface() {
-			switch v.Type().Field(i).Name {
-			case "PageNum", "Num":
-				if v.Field(i).CanConvert(reflect.TypeOf(q.Num)) {
-					q.Num = v.Field(i).Convert(reflect.TypeOf(q.Num)).Interface().(int)
-					continue
-				}
-				return q, ErrInvalidPageNum
-			case "PageSize", "Size":
-				if v.Field(i).CanConvert(reflect.TypeOf(q.Size)) {
-					q.Size = v.Field(i).Convert(reflect.TypeOf(q.Size)).Interface().(int)
-					continue
-				}
-				return q, ErrInvalidPageSize
-			case "OrderBy":
-				if val, ok := v.Field(i).Interface().(string); ok {
-					q.OrderBy = val
-					continue
-				}
+	var (
+		sortOrder string
+		err       error
+	)
+	for _, name := range fieldNames(v.Type()) {
+		f, ok := field(v, name, false)
+		if !ok || !f.CanInterface() {
+			continue
+		}
+		f, set := requestValue(f)
+		switch name {
+		case "PageNum", "Num":
+			if !set {
+				continue
+			}
+			n, err := parseInt(f)
+			if err != nil {
+				return q, wrapParseError(ErrInvalidPageNum, err)
+			}
+			q.Num = n
+		case "PageSize", "Size":
+			if !set {
+				continue
+			}
+			n, err := parseInt(f)
+			if err != nil {
+				return q, wrapParseError(ErrInvalidPageSize, err)
+			}
+			q.Size = n
+		case "OrderBy":
+			if !set {
+				continue
+			}
+			if f.Kind() != reflect.String {
 				return q, ErrInvalidOrderBy
-
-			case "IsDescending", "Descending":
-				if val, ok := v.Field(i).Interface().(bool); ok {
-					q.IsDescending = val
-					continue
-				}
+			}
+			q.OrderBy = f.String()
+		case "IsDescending", "Descending":
+			if !set {
+				continue
+			}
+			if f.Kind() != reflect.Bool {
 				return q, ErrInvalidIsDescending
-			case "Query", "SearchKey":
-				if val, ok := v.Field(i).Interface().(string); ok {
-					q.Query = val
-					continue
-				}
+			}
+			q.IsDescending = f.Bool()
+		case "Query", "SearchKey":
+			if !set {
+				continue
+			}
+			if f.Kind() != reflect.String {
 				return q, ErrInvalidSearchKey
 			}
+			q.Query = f.String()
+		case "PageToken":
+			if !set {
+				continue
+			}
+			if f.Kind() != reflect.String {
+				return q, ErrInvalidPageToken
+			}
+			q.Token = f.String()
+		// Filter, ShowTotal and Sort are common names, fields of other types
+		// are left alone like ParseProto does
+		case "Filter":
+			if set && f.Kind() == reflect.String {
+				q.Filter = f.String()
+			}
+		case "ShowTotal":
+			if set && f.Kind() == reflect.Bool {
+				q.ShowTotal = f.Bool()
+			}
+		case "Sort":
+			if !set {
+				continue
+			}
+			switch sort := f.Interface().(type) {
+			case []*SortField:
+				if sortOrder, err = sortOrderBy(sort); err != nil {
+					return q, err
+				}
+			case []SortKey:
+				sortOrder = keysOrderBy(sort)
+			}
 		}
 	}
+	// sort takes precedence over OrderBy
+	if sortOrder != "" {
+		q.OrderBy = sortOrder
+	}
 
-	for i := range options {
-		if err := options[i](&q); err != nil {
-			return q, err
+	return q, q.apply(options)
+}
+
+// requestValue unwraps pointers, sql.Null* types and protobuf wrappers of a
+// request field, set is false when the field is nil or not valid.
+func requestValue(f reflect.Value) (value reflect.Value, set bool) {
+	for {
+		switch f.Kind() {
+		case reflect.Ptr:
+			if f.IsNil() {
+				return f, false
+			}
+			f = f.Elem()
+		case reflect.Struct:
+			value, valid, ok := wrappedValue(f)
+			if !ok {
+				return f, true
+			}
+			if valid.IsValid() && !valid.Bool() {
+				return f, false
+			}
+			f = value
+		default:
+			return f, true
 		}
 	}
+}
+
+// errNotNumber is returned by parseInt for fields which can't hold a number.
+var errNotNumber = errors.New("not a number")
 
-	return q, nil
+// parseInt converts a number or a numeric string to int, an empty string is 0.
+func parseInt(f reflect.Value) (int, error) {
+	if f.Kind() == reflect.String {
+		return parseString(f.String())
+	}
+	if f.CanConvert(reflect.TypeOf(0)) {
+		return int(f.Convert(reflect.TypeOf(0)).Int()), nil
+	}
+	return 0, errNotNumber
+}
+
+// parseString converts a numeric string to int, an empty string is 0.
+func parseString(s string) (int, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(trimmed)
+	if err != nil {
+		return 0, errors.Errorf("%q is not a number", s)
+	}
+	return n, nil
+}
+
+// ParseInt converts a numeric string field of a request to int the way Parse
+// does, an empty string is 0. Invalid strings return sentinel, e.g.
+// ErrInvalidPageNum, wrapped with the cause. It is used by generated code.
+func ParseInt(s string, sentinel error) (int, error) {
+	n, err := parseString(s)
+	if err != nil {
+		return 0, wrapParseError(sentinel, err)
+	}
+	return n, nil
+}
+
+// wrapParseError returns the sentinel itself for fields of the wrong type, so
+// callers can compare it directly, and wraps it with the cause otherwise.
+func wrapParseError(sentinel, err error) error {
+	if err == errNotNumber {
+		return sentinel
+	}
+	return errors.Wrap(sentinel, err.Error())
+}
+
+// New returns p with the default size and the options applied, for pages
+// built without Parse, e.g. by generated code.
+func New(p Page, options ...Option) (Page, error) {
+	if p.defaultSize == 0 {
+		p.defaultSize = 15
+	}
+	return p, p.apply(options)
+}
+
+// apply applies the options and checks the resulting page.
+func (p *Page) apply(options []Option) error {
+	for i := range options {
+		if err := options[i](p); err != nil {
+			return err
+		}
+	}
+	return p.checkOverflow()
 }
 
 func WithDefaultSize(size int) Option {
@@ -89,3 +238,57 @@ func WithDefaultSize(size int) Option {
 		return nil
 	}
 }
+
+// WithProbe turns on the total-less mode: Limit returns ProbeLimit, so one
+// extra row is fetched to detect the next page, and FillResponse leaves
+// Total and LastPage untouched unless SetTotal was called.
+func WithProbe() Option {
+	return func(p *Page) error {
+		p.probe = true
+		return nil
+	}
+}
+
+// WithAllocate sets whether FillResponse and FillResponseAt allocate nil
+// pointers to the pagination block, e.g. a nil Page *PaginationResponse.
+// By default only protobuf messages are allocated.
+func WithAllocate(allocate bool) Option {
+	return func(p *Page) error {
+		p.allocate = allocate
+		p.allocateSet = true
+		return nil
+	}
+}
+
+// WithMaxSize caps the page size and the default size to size.
+func WithMaxSize(size int) Option {
+	return func(p *Page) error {
+		if p.Size > size {
+			p.Size = size
+		}
+		if p.defaultSize > size {
+			p.defaultSize = size
+		}
+		return nil
+	}
+}
+
+// WithSortable makes Parse reject orders by fields other than fields.
+func WithSortable(fields ...string) Option {
+	return func(p *Page) error {
+		keys, err := p.SortKeys()
+		if err != nil {
+			return err
+		}
+	next:
+		for _, key := range keys {
+			for _, field := range fields {
+				if key.Field == field {
+					continue next
+				}
+			}
+			return errors.Wrapf(ErrInvalidOrderBy, "%q is not sortable", key.Field)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,439 @@
+package pagination
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+var (
+	_protoRequestFields  = []string{"page", "pagination", "page_request", "pagination_request"}
+	_protoResponseFields = []string{"page", "pagination"}
+)
+
+// ParseProto parses a protobuf message like Parse does, but through
+// protoreflect, so dynamic messages work too. Fields are matched by their
+// (pagination.field) option, or by their proto name or json_name, e.g.
+// page_num or pageNum. Unset optional fields and wrappers count as unset.
+//
+// The (pagination.policy) option of the message, or else of the nested page
+// message, is applied before options, see WithDefaultSize, WithMaxSize and
+// WithSortable.
+func ParseProto(m proto.Message, options ...Option) (Page, error) {
+	q := Page{
+		defaultSize: 15,
+	}
+	if m == nil {
+		return q, ErrInvalidParseData
+	}
+	msg := m.ProtoReflect()
+	policy := messagePolicy(msg.Descriptor())
+	for !isProtoRequest(msg) {
+		fd := protoField(msg, _protoRequestFields...)
+		if fd == nil || fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			break
+		}
+		if policy == nil {
+			policy = messagePolicy(fd.Message())
+		}
+		if !msg.Has(fd) {
+			return q, q.apply(append(policy.options(), options...))
+		}
+		msg = msg.Get(fd).Message()
+	}
+
+	if v, fd, ok := protoValue(msg, PageField_PAGE_NUM, "page_num", "num"); ok {
+		n, err := protoInt(v, fd)
+		if err != nil {
+			return q, wrapParseError(ErrInvalidPageNum, err)
+		}
+		q.Num = n
+	}
+	if v, fd, ok := protoValue(msg, PageField_PAGE_SIZE, "page_size", "size"); ok {
+		n, err := protoInt(v, fd)
+		if err != nil {
+			return q, wrapParseError(ErrInvalidPageSize, err)
+		}
+		q.Size = n
+	}
+	if v, fd, ok := protoValue(msg, PageField_ORDER_BY, "order_by"); ok {
+		if fd.Kind() != protoreflect.StringKind {
+			return q, ErrInvalidOrderBy
+		}
+		q.OrderBy = v.String()
+	}
+	if v, fd, ok := protoValue(msg, PageField_IS_DESCENDING, "is_descending", "descending"); ok {
+		if fd.Kind() != protoreflect.BoolKind {
+			return q, ErrInvalidIsDescending
+		}
+		q.IsDescending = v.Bool()
+	}
+	if v, fd, ok := protoValue(msg, PageField_QUERY, "query", "search_key"); ok {
+		if fd.Kind() != protoreflect.StringKind {
+			return q, ErrInvalidSearchKey
+		}
+		q.Query = v.String()
+	}
+	if v, fd, ok := protoValue(msg, PageField_PAGE_TOKEN, "page_token"); ok {
+		if fd.Kind() != protoreflect.StringKind {
+			return q, ErrInvalidPageToken
+		}
+		q.Token = v.String()
+	}
+	// fields called filter or show_total of other types are left alone,
+	// unless they are marked for it
+	if v, fd, ok := protoValue(msg, PageField_FILTER, "filter"); ok {
+		if fd.Kind() == protoreflect.StringKind {
+			q.Filter = v.String()
+		} else if markedField(msg, PageField_FILTER) != nil {
+			return q, ErrInvalidFilter
+		}
+	}
+	if v, fd, ok := protoValue(msg, PageField_SHOW_TOTAL, "show_total"); ok {
+		if fd.Kind() == protoreflect.BoolKind {
+			q.ShowTotal = v.Bool()
+		} else if markedField(msg, PageField_SHOW_TOTAL) != nil {
+			return q, ErrInvalidShowTotal
+		}
+	}
+	sort, err := protoSort(msg)
+	if err != nil {
+		return q, err
+	}
+	// sort takes precedence over order_by
+	if sort != "" {
+		q.OrderBy = sort
+	}
+	return q, q.apply(append(policy.options(), options...))
+}
+
+// protoSort returns the order of the repeated pagination.SortField field sort
+// of msg, "" when there is none. Other fields called sort are left alone.
+func protoSort(msg protoreflect.Message) (string, error) {
+	fd := protoField(msg, "sort")
+	if fd == nil || !fd.IsList() || fd.Kind() != protoreflect.MessageKind ||
+		fd.Message().FullName() != (*SortField)(nil).ProtoReflect().Descriptor().FullName() {
+		return "", nil
+	}
+	list := msg.Get(fd).List()
+	sort := make([]*SortField, list.Len())
+	for i := range sort {
+		elem := list.Get(i).Message().Interface()
+		if f, ok := elem.(*SortField); ok {
+			sort[i] = f
+			continue
+		}
+		// dynamic messages share the wire format of SortField
+		data, err := proto.Marshal(elem)
+		if err != nil {
+			return "", errors.Wrap(ErrInvalidOrderBy, err.Error())
+		}
+		sort[i] = &SortField{}
+		if err := proto.Unmarshal(data, sort[i]); err != nil {
+			return "", errors.Wrap(ErrInvalidOrderBy, err.Error())
+		}
+	}
+	return sortOrderBy(sort)
+}
+
+// isProtoRequest reports whether msg holds the pagination fields itself.
+func isProtoRequest(msg protoreflect.Message) bool {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if pageField(fields.Get(i)) != PageField_PAGE_FIELD_UNSPECIFIED {
+			return true
+		}
+	}
+	return protoField(msg, "page_num", "num") != nil && protoField(msg, "page_size", "size") != nil
+}
+
+// pageField returns the (pagination.field) option of fd.
+func pageField(fd protoreflect.FieldDescriptor) PageField {
+	opts := protoOptions(fd.Options(), E_Field)
+	if opts == nil {
+		return PageField_PAGE_FIELD_UNSPECIFIED
+	}
+	return proto.GetExtension(opts, E_Field).(PageField)
+}
+
+// messagePolicy returns the (pagination.policy) option of md, or nil.
+func messagePolicy(md protoreflect.MessageDescriptor) *PagePolicy {
+	opts := protoOptions(md.Options(), E_Policy)
+	if opts == nil {
+		return nil
+	}
+	return proto.GetExtension(opts, E_Policy).(*PagePolicy)
+}
+
+// protoOptions returns the options holding the extension xt, or nil. Options
+// of descriptors built without the extension registered keep it in their
+// unknown fields, which are parsed again.
+func protoOptions(opts proto.Message, xt protoreflect.ExtensionType) proto.Message {
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return nil
+	}
+	if proto.HasExtension(opts, xt) {
+		return opts
+	}
+	if len(opts.ProtoReflect().GetUnknown()) == 0 {
+		return nil
+	}
+	data, err := proto.Marshal(opts)
+	if err != nil {
+		return nil
+	}
+	parsed := opts.ProtoReflect().Type().New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}).Unmarshal(data, parsed); err != nil {
+		return nil
+	}
+	if !proto.HasExtension(parsed, xt) {
+		return nil
+	}
+	return parsed
+}
+
+// options returns the options applying the policy.
+func (p *PagePolicy) options() []Option {
+	var options []Option
+	if p.GetDefaultSize() > 0 {
+		options = append(options, WithDefaultSize(int(p.GetDefaultSize())))
+	}
+	if p.GetMaxSize() > 0 {
+		options = append(options, WithMaxSize(int(p.GetMaxSize())))
+	}
+	if len(p.GetSortable()) > 0 {
+		options = append(options, WithSortable(p.GetSortable()...))
+	}
+	return options
+}
+
+// FillProto fills a protobuf response like FillResponse does, but through
+// protoreflect. Unset nested page messages are allocated unless
+// WithAllocate(false) was given, unset wrappers are always allocated.
+func (p Page) FillProto(m proto.Message) error {
+	if m == nil {
+		return ErrInvalidResponse
+	}
+	msg := m.ProtoReflect()
+	if !msg.IsValid() {
+		return ErrInvalidResponse
+	}
+	seen := map[protoreflect.FullName]bool{}
+	for protoField(msg, "total", "has_next") == nil ||
+		protoField(msg, "page_num", "num", "current_page") == nil && protoField(msg, "page_size", "size") == nil {
+		seen[msg.Descriptor().FullName()] = true
+		fd := protoField(msg, _protoResponseFields...)
+		if fd == nil || fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() || seen[fd.Message().FullName()] {
+			return ErrInvalidResponse
+		}
+		if !msg.Has(fd) && p.allocateSet && !p.allocate {
+			return ErrInvalidResponse
+		}
+		msg = msg.Mutable(fd).Message()
+	}
+
+	if fd := protoField(msg, "total"); fd != nil && p.totalKnown() {
+		if err := p.setProtoNumber(msg, fd, int64(p.Total)); err != nil {
+			return errors.Wrap(err, string(fd.Name()))
+		}
+	}
+	if fd := protoField(msg, "page_num", "num", "current_page"); fd != nil {
+		if err := p.setProtoNumber(msg, fd, int64(p.Num)); err != nil {
+			return errors.Wrap(err, string(fd.Name()))
+		}
+	}
+	// the size of an unlimited page is the total
+	if fd := protoField(msg, "page_size", "size"); fd != nil && (p.Size != 0 || p.totalKnown()) {
+		size := p.Size
+		if size == 0 {
+			size = p.Total
+		}
+		if err := p.setProtoNumber(msg, fd, int64(size)); err != nil {
+			return errors.Wrap(err, string(fd.Name()))
+		}
+	}
+	for _, name := range []string{"last_page", "total_pages"} {
+		if fd := protoField(msg, name); fd != nil && p.totalKnown() {
+			if err := p.setProtoNumber(msg, fd, int64(p.LastPage())); err != nil {
+				return errors.Wrap(err, string(fd.Name()))
+			}
+		}
+	}
+	for _, f := range []struct {
+		names []string
+		value protoreflect.Value
+	}{
+		{[]string{"has_next"}, protoreflect.ValueOfBool(p.HasNext())},
+		{[]string{"next_page_token"}, protoreflect.ValueOfString(p.NextToken)},
+		{[]string{"total_is_capped", "total_capped"}, protoreflect.ValueOfBool(p.TotalIsCapped)},
+		{[]string{"total_is_estimate", "is_estimate"}, protoreflect.ValueOfBool(p.TotalIsEstimate)},
+	} {
+		if fd := protoField(msg, f.names...); fd != nil {
+			if err := setProtoValue(msg, fd, f.value); err != nil {
+				return errors.Wrap(err, string(fd.Name()))
+			}
+		}
+	}
+	return nil
+}
+
+// protoField returns the first field of msg called one of names, matched by
+// proto name or json_name.
+func protoField(msg protoreflect.Message, names ...string) protoreflect.FieldDescriptor {
+	fields := msg.Descriptor().Fields()
+	for _, name := range names {
+		if fd := fields.ByName(protoreflect.Name(name)); fd != nil {
+			return fd
+		}
+		if fd := fields.ByJSONName(name); fd != nil {
+			return fd
+		}
+		if fd := fields.ByJSONName(lowerCamelCase(name)); fd != nil {
+			return fd
+		}
+	}
+	return nil
+}
+
+// markedField returns the field of msg with the (pagination.field) option
+// field, or nil.
+func markedField(msg protoreflect.Message, field PageField) protoreflect.FieldDescriptor {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if pageField(fields.Get(i)) == field {
+			return fields.Get(i)
+		}
+	}
+	return nil
+}
+
+// protoValue returns the value of the field of msg marked as field, or else
+// of the first field called one of names, unwrapping well-known wrappers. ok
+// is false when the field is missing or not set, for fields with presence.
+func protoValue(msg protoreflect.Message, field PageField, names ...string) (v protoreflect.Value, fd protoreflect.FieldDescriptor, ok bool) {
+	fd = markedField(msg, field)
+	if fd == nil {
+		// fields marked for another pagination field aren't matched by name
+		if fd = protoField(msg, names...); fd != nil && pageField(fd) != PageField_PAGE_FIELD_UNSPECIFIED {
+			return v, nil, false
+		}
+	}
+	if fd == nil || fd.IsList() || fd.IsMap() {
+		return v, fd, false
+	}
+	if fd.HasPresence() && !msg.Has(fd) {
+		return v, fd, false
+	}
+	v = msg.Get(fd)
+	if wrapped := wrapperField(fd); wrapped != nil {
+		return v.Message().Get(wrapped), wrapped, true
+	}
+	return v, fd, true
+}
+
+// wrapperField returns the value field of a well-known wrapper message field
+// such as google.protobuf.Int32Value.
+func wrapperField(fd protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if fd.Kind() != protoreflect.MessageKind {
+		return nil
+	}
+	md := fd.Message()
+	if md.ParentFile() == nil || md.ParentFile().Package() != "google.protobuf" ||
+		!strings.HasSuffix(string(md.Name()), "Value") || md.Fields().Len() != 1 {
+		return nil
+	}
+	return md.Fields().ByName("value")
+}
+
+func protoInt(v protoreflect.Value, fd protoreflect.FieldDescriptor) (int, error) {
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return int(v.Int()), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if v.Uint() > math.MaxInt64 {
+			return 0, errors.Errorf("%d overflows int", v.Uint())
+		}
+		return int(v.Uint()), nil
+	case protoreflect.StringKind:
+		return parseString(v.String())
+	}
+	return 0, errNotNumber
+}
+
+// setProtoNumber sets n to the field fd of msg, following the overflow
+// policy of the page.
+func (p Page) setProtoNumber(msg protoreflect.Message, fd protoreflect.FieldDescriptor, n int64) error {
+	if wrapped := wrapperField(fd); wrapped != nil {
+		return p.setProtoNumber(msg.Mutable(fd).Message(), wrapped, n)
+	}
+	if fd.IsList() || fd.IsMap() {
+		return ErrResponseFieldType
+	}
+	var v protoreflect.Value
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n > math.MaxInt32 || n < math.MinInt32 {
+			if p.overflow == OverflowError {
+				return ErrNumberOverflow
+			}
+		}
+		v = protoreflect.ValueOfInt32(clampInt32(n))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v = protoreflect.ValueOfInt64(n)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n < 0 || n > math.MaxUint32 {
+			if p.overflow == OverflowError {
+				return ErrNumberOverflow
+			}
+			n = max(0, min(n, math.MaxUint32))
+		}
+		v = protoreflect.ValueOfUint32(uint32(n))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n < 0 {
+			if p.overflow == OverflowError {
+				return ErrNumberOverflow
+			}
+			n = 0
+		}
+		v = protoreflect.ValueOfUint64(uint64(n))
+	case protoreflect.FloatKind:
+		v = protoreflect.ValueOfFloat32(float32(n))
+	case protoreflect.DoubleKind:
+		v = protoreflect.ValueOfFloat64(float64(n))
+	case protoreflect.StringKind:
+		v = protoreflect.ValueOfString(strconv.FormatInt(n, 10))
+	default:
+		return ErrResponseFieldType
+	}
+	msg.Set(fd, v)
+	return nil
+}
+
+// setProtoValue sets a bool or string value to the field fd of msg or to the
+// wrapper it holds.
+func setProtoValue(msg protoreflect.Message, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	if wrapped := wrapperField(fd); wrapped != nil {
+		return setProtoValue(msg.Mutable(fd).Message(), wrapped, v)
+	}
+	if fd.IsList() || fd.IsMap() {
+		return ErrResponseFieldType
+	}
+	switch v.Interface().(type) {
+	case bool:
+		if fd.Kind() != protoreflect.BoolKind {
+			return ErrResponseFieldType
+		}
+	case string:
+		if fd.Kind() != protoreflect.StringKind {
+			return ErrResponseFieldType
+		}
+	}
+	msg.Set(fd, v)
+	return nil
+}
@@ -0,0 +1,385 @@
+package pagination
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// testProtoFile describes the messages of the protoreflect tests:
+//
+//	message Paging {
+//	  optional int32 page_num = 1;
+//	  google.protobuf.Int32Value page_size = 2;
+//	  string order_by = 3 [json_name = "sortBy"];
+//	  bool descending = 4;
+//	  string search_key = 5;
+//	}
+//	message ListRequest { Paging pagination = 1; string filter = 2; }
+//	message FormRequest {
+//	  string page_num = 1;
+//	  string page_size = 2;
+//	  int32 filter = 3;
+//	  string show_total = 4;
+//	  string sort = 5;
+//	  string token = 6;
+//	}
+//	message Meta {
+//	  google.protobuf.Int64Value total = 1;
+//	  uint32 current = 2 [json_name = "pageNum"];
+//	  int32 size = 3;
+//	  optional int64 last_page = 4;
+//	  google.protobuf.BoolValue has_next = 5;
+//	  string next_page_token = 6;
+//	}
+//	message ListResponse { Meta page = 1; repeated string items = 2; }
+//	message AnnotatedRequest {
+//	  option (pagination.policy) = {default_size: 20, max_size: 100, sortable: ["name", "created_at"]};
+//	  int32 limit = 1 [(pagination.field) = PAGE_SIZE];
+//	  uint32 page = 2 [(pagination.field) = PAGE_NUM];
+//	  string sort = 3 [(pagination.field) = ORDER_BY];
+//	  int64 page_size = 4;
+//	}
+//	message SearchRequest { AnnotatedRequest page_request = 1; }
+var testProtoFile = sync.OnceValue(func() protoreflect.FileDescriptor {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   typ.Enum(),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	optional := func(f *descriptorpb.FieldDescriptorProto, oneof int32) *descriptorpb.FieldDescriptorProto {
+		f.Proto3Optional = proto.Bool(true)
+		f.OneofIndex = proto.Int32(oneof)
+		return f
+	}
+	withJSON := func(f *descriptorpb.FieldDescriptorProto, name string) *descriptorpb.FieldDescriptorProto {
+		f.JsonName = proto.String(name)
+		return f
+	}
+	const (
+		int32Type   = descriptorpb.FieldDescriptorProto_TYPE_INT32
+		int64Type   = descriptorpb.FieldDescriptorProto_TYPE_INT64
+		uint32Type  = descriptorpb.FieldDescriptorProto_TYPE_UINT32
+		stringType  = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		boolType    = descriptorpb.FieldDescriptorProto_TYPE_BOOL
+		messageType = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	)
+	marked := func(f *descriptorpb.FieldDescriptorProto, pageField PageField) *descriptorpb.FieldDescriptorProto {
+		f.Options = &descriptorpb.FieldOptions{}
+		proto.SetExtension(f.Options, E_Field, pageField)
+		return f
+	}
+	policy := &descriptorpb.MessageOptions{}
+	proto.SetExtension(policy, E_Policy, &PagePolicy{DefaultSize: 20, MaxSize: 100, Sortable: []string{"name", "created_at"}})
+	items := field("items", 2, stringType, "")
+	items.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("pagination_test.proto"),
+		Package:    proto.String("pagination.test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/wrappers.proto", "pagination.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Paging"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					optional(field("page_num", 1, int32Type, ""), 0),
+					field("page_size", 2, messageType, ".google.protobuf.Int32Value"),
+					withJSON(field("order_by", 3, stringType, ""), "sortBy"),
+					field("descending", 4, boolType, ""),
+					field("search_key", 5, stringType, ""),
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_page_num")}},
+			},
+			{
+				Name: proto.String("ListRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("pagination", 1, messageType, ".pagination.test.Paging"),
+					field("filter", 2, stringType, ""),
+				},
+			},
+			{
+				Name: proto.String("FormRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("page_num", 1, stringType, ""),
+					field("page_size", 2, stringType, ""),
+					field("filter", 3, int32Type, ""),
+					field("show_total", 4, stringType, ""),
+					field("sort", 5, stringType, ""),
+					field("token", 6, stringType, ""),
+				},
+			},
+			{
+				Name: proto.String("Meta"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("total", 1, messageType, ".google.protobuf.Int64Value"),
+					withJSON(field("current", 2, uint32Type, ""), "pageNum"),
+					field("size", 3, int32Type, ""),
+					optional(field("last_page", 4, int64Type, ""), 0),
+					field("has_next", 5, messageType, ".google.protobuf.BoolValue"),
+					field("next_page_token", 6, stringType, ""),
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_last_page")}},
+			},
+			{
+				Name: proto.String("ListResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("page", 1, messageType, ".pagination.test.Meta"),
+					items,
+				},
+			},
+			{
+				Name: proto.String("AnnotatedRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					marked(field("limit", 1, int32Type, ""), PageField_PAGE_SIZE),
+					marked(field("page", 2, uint32Type, ""), PageField_PAGE_NUM),
+					marked(field("sort", 3, stringType, ""), PageField_ORDER_BY),
+					field("page_size", 4, int64Type, ""),
+				},
+				Options: policy,
+			},
+			{
+				Name: proto.String("SearchRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("page_request", 1, messageType, ".pagination.test.AnnotatedRequest"),
+				},
+			},
+		},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		panic(err)
+	}
+	return fd
+})
+
+func newTestMessage(name protoreflect.Name) *dynamicpb.Message {
+	return dynamicpb.NewMessage(testProtoFile().Messages().ByName(name))
+}
+
+func setTestField(msg protoreflect.Message, name protoreflect.Name, v interface{}) {
+	fd := msg.Descriptor().Fields().ByName(name)
+	if m, ok := v.(proto.Message); ok {
+		msg.Set(fd, protoreflect.ValueOfMessage(m.ProtoReflect()))
+		return
+	}
+	msg.Set(fd, protoreflect.ValueOf(v))
+}
+
+func TestParseProto(t *testing.T) {
+	paging := newTestMessage("Paging")
+	setTestField(paging, "page_num", int32(3))
+	setTestField(paging, "page_size", wrapperspb.Int32(20))
+	setTestField(paging, "order_by", "name")
+	setTestField(paging, "descending", true)
+	setTestField(paging, "search_key", "q")
+	req := newTestMessage("ListRequest")
+	setTestField(req, "pagination", paging)
+
+	page, err := ParseProto(req)
+	require.NoError(t, err)
+	assert.Equal(t, Page{Num: 3, Size: 20, OrderBy: "name", IsDescending: true, Query: "q", defaultSize: 15}, page)
+
+	// unset optional fields and wrappers keep the defaults
+	paging = newTestMessage("Paging")
+	setTestField(paging, "page_num", int32(2))
+	setTestField(req, "pagination", paging)
+	page, err = ParseProto(req, WithDefaultSize(30))
+	require.NoError(t, err)
+	assert.Equal(t, Page{Num: 2, defaultSize: 30}, page)
+	assert.Equal(t, int32(30), page.Limit())
+
+	page, err = ParseProto(newTestMessage("ListRequest"))
+	require.NoError(t, err)
+	assert.Equal(t, Page{defaultSize: 15}, page)
+	page, err = ParseProto(newTestMessage("Paging"))
+	require.NoError(t, err)
+	assert.Equal(t, Page{defaultSize: 15}, page)
+
+	// generated messages
+	page, err = ParseProto(&PaginationRequest{PageNum: 10, PageSize: 50, OrderBy: "id", IsDescending: true, Query: "search"})
+	require.NoError(t, err)
+	assert.Equal(t, targetPage, page)
+
+	// numeric strings
+	form := newTestMessage("FormRequest")
+	setTestField(form, "page_num", "4")
+	setTestField(form, "page_size", "25")
+	// filter, show_total and sort of other types are left alone, as are
+	// tokens other than page_token
+	setTestField(form, "filter", int32(7))
+	setTestField(form, "show_total", "yes")
+	setTestField(form, "sort", "name")
+	setTestField(form, "token", "secret")
+	page, err = ParseProto(form)
+	require.NoError(t, err)
+	assert.Equal(t, Page{Num: 4, Size: 25, defaultSize: 15}, page)
+	setTestField(form, "page_size", "many")
+	_, err = ParseProto(form)
+	assert.ErrorIs(t, err, ErrInvalidPageSize)
+
+	_, err = ParseProto(nil)
+	assert.Equal(t, ErrInvalidParseData, err)
+}
+
+func TestPage_FillProto(t *testing.T) {
+	page := Page{Num: 2, Size: 10, NextToken: "next"}
+	page.SetTotal(25)
+
+	resp := newTestMessage("ListResponse")
+	require.NoError(t, page.FillProto(resp))
+	meta := resp.Get(resp.Descriptor().Fields().ByName("page")).Message()
+	get := func(name protoreflect.Name) protoreflect.Value {
+		return meta.Get(meta.Descriptor().Fields().ByName(name))
+	}
+	value := func(name protoreflect.Name) protoreflect.Value {
+		wrapper := get(name).Message()
+		return wrapper.Get(wrapper.Descriptor().Fields().ByName("value"))
+	}
+	assert.Equal(t, int64(25), value("total").Int())
+	assert.Equal(t, uint64(2), get("current").Uint())
+	assert.Equal(t, int64(10), get("size").Int())
+	assert.Equal(t, int64(3), get("last_page").Int())
+	assert.True(t, value("has_next").Bool())
+	assert.Equal(t, "next", get("next_page_token").String())
+
+	// the total is left unset when unknown
+	probe, err := ParseProto(&PaginationRequest{PageNum: 1, PageSize: 10}, WithProbe())
+	require.NoError(t, err)
+	probe.Trim(3)
+	resp = newTestMessage("ListResponse")
+	require.NoError(t, probe.FillProto(resp))
+	meta = resp.Get(resp.Descriptor().Fields().ByName("page")).Message()
+	assert.False(t, meta.Has(meta.Descriptor().Fields().ByName("total")))
+	assert.False(t, meta.Has(meta.Descriptor().Fields().ByName("last_page")))
+	assert.False(t, value("has_next").Bool())
+
+	// as is the size of an unlimited page
+	probe, err = ParseProto(&PaginationRequest{}, WithProbe())
+	require.NoError(t, err)
+	pbResp := &PaginationResponse{PageSize: -1}
+	require.NoError(t, probe.FillProto(pbResp))
+	assert.Equal(t, int64(-1), pbResp.PageSize)
+
+	// generated messages
+	pbResp = &PaginationResponse{}
+	require.NoError(t, page.FillProto(pbResp))
+	assert.True(t, proto.Equal(&PaginationResponse{
+		Total: 25, PageNum: 2, LastPage: 3, PageSize: 10, NextPageToken: "next", HasNext: true, TotalPages: 3,
+	}, pbResp))
+
+	// overflow
+	big := Page{Num: 1, Size: 1 << 40}
+	assert.NoError(t, big.FillProto(newTestMessage("Meta")))
+	big.overflow = OverflowError
+	assert.ErrorIs(t, big.FillProto(newTestMessage("Meta")), ErrNumberOverflow)
+
+	page.allocate, page.allocateSet = false, true
+	assert.ErrorIs(t, page.FillProto(newTestMessage("ListResponse")), ErrInvalidResponse)
+	assert.ErrorIs(t, page.FillProto(newTestMessage("Paging")), ErrInvalidResponse)
+	assert.ErrorIs(t, page.FillProto(nil), ErrInvalidResponse)
+}
+
+func TestParseProto_Options(t *testing.T) {
+	req := newTestMessage("AnnotatedRequest")
+	setTestField(req, "limit", int32(30))
+	setTestField(req, "page", uint32(2))
+	setTestField(req, "sort", "name, created_at desc")
+	setTestField(req, "page_size", int64(5))
+	page, err := ParseProto(req)
+	require.NoError(t, err)
+	assert.Equal(t, Page{Num: 2, Size: 30, OrderBy: "name, created_at desc", defaultSize: 20}, page)
+
+	// the policy caps the size and restricts the order
+	setTestField(req, "limit", int32(1000))
+	page, err = ParseProto(req)
+	require.NoError(t, err)
+	assert.Equal(t, 100, page.Size)
+	setTestField(req, "sort", "id")
+	_, err = ParseProto(req)
+	assert.ErrorIs(t, err, ErrInvalidOrderBy)
+
+	// options given to ParseProto are applied after the policy
+	setTestField(req, "sort", "")
+	setTestField(req, "limit", int32(0))
+	page, err = ParseProto(req, WithDefaultSize(50))
+	require.NoError(t, err)
+	assert.Equal(t, int32(50), page.Limit())
+
+	// the policy of a nested page message, also when it is unset
+	search := newTestMessage("SearchRequest")
+	page, err = ParseProto(search)
+	require.NoError(t, err)
+	assert.Equal(t, Page{defaultSize: 20}, page)
+	setTestField(search, "page_request", req)
+	page, err = ParseProto(search)
+	require.NoError(t, err)
+	assert.Equal(t, Page{Num: 2, defaultSize: 20}, page)
+
+	// options which were parsed without the extensions being known
+	opts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(opts, E_Field, PageField_PAGE_SIZE)
+	data, err := proto.Marshal(opts)
+	require.NoError(t, err)
+	unknown := &descriptorpb.FieldOptions{}
+	require.NoError(t, proto.UnmarshalOptions{Resolver: &protoregistry.Types{}}.Unmarshal(data, unknown))
+	require.False(t, proto.HasExtension(unknown, E_Field))
+	assert.Equal(t, PageField_PAGE_SIZE, proto.GetExtension(protoOptions(unknown, E_Field), E_Field))
+	assert.Nil(t, protoOptions(&descriptorpb.FieldOptions{}, E_Field))
+}
+
+func TestWithSortable(t *testing.T) {
+	_, err := Parse(&testRequest{OrderBy: "name, -id"}, WithSortable("name", "id"))
+	assert.NoError(t, err)
+	_, err = Parse(&testRequest{OrderBy: "name, email"}, WithSortable("name", "id"))
+	assert.EqualError(t, err, `"email" is not sortable: invalid order`)
+	_, err = Parse(&testRequest{OrderBy: "name,"}, WithSortable("name"))
+	assert.ErrorIs(t, err, ErrInvalidOrderBy)
+
+	page, err := Parse(&testRequest{PageNum: 1, PageSize: 500}, WithMaxSize(100))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(100), page.Limit())
+	page, err = Parse(&testRequest{PageNum: 1}, WithMaxSize(10))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), page.Limit())
+}
+
+func TestParseProto_DynamicSort(t *testing.T) {
+	req := &PaginationRequest{
+		PageNum: 1,
+		Sort:    []*SortField{{Field: "name", Direction: SortField_DESC}, {Field: "id"}},
+		Filter:  "active",
+	}
+	data, err := proto.Marshal(req)
+	require.NoError(t, err)
+	dynamic := dynamicpb.NewMessage(req.ProtoReflect().Descriptor())
+	require.NoError(t, proto.Unmarshal(data, dynamic))
+
+	page, err := ParseProto(dynamic)
+	require.NoError(t, err)
+	assert.Equal(t, Page{Num: 1, OrderBy: "name desc, id", Filter: "active", defaultSize: 15}, page)
+}
+
+func TestPage_FillProto_Extended(t *testing.T) {
+	page := Page{Num: 2, Size: 10, NextToken: "next", TotalIsEstimate: true}
+	page.SetTotal(25)
+	resp := &PaginationResponse{}
+	require.NoError(t, page.FillProto(resp))
+	assert.True(t, proto.Equal(page.ToResponseProto(), resp))
+	assert.Equal(t, int64(3), resp.TotalPages)
+	assert.True(t, resp.TotalIsEstimate)
+}
@@ -0,0 +1,173 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Result is a typed response envelope, an alternative to FillResponse for
+// services which don't need their own response structs.
+type Result[T any] struct {
+	Items         []T    `json:"items"`
+	Total         int64  `json:"total"`
+	PageNum       int64  `json:"page_num"`
+	PageSize      int64  `json:"page_size"`
+	LastPage      int64  `json:"last_page"`
+	NextPageToken string `json:"next_page_token,omitempty"`
+	HasNext       bool   `json:"has_next"`
+}
+
+// NewResult builds a Result from the page, filled the same way as FillResponse does.
+func NewResult[T any](p Page, items []T) Result[T] {
+	if items == nil {
+		items = []T{}
+	}
+	r := Result[T]{
+		Items:         items,
+		PageNum:       int64(p.Num),
+		PageSize:      int64(p.Size),
+		NextPageToken: p.NextToken,
+		HasNext:       p.HasNext(),
+	}
+	if p.totalKnown() {
+		r.Total = int64(p.Total)
+		r.LastPage = int64(p.LastPage())
+		if p.Size == 0 {
+			r.PageSize = int64(p.Total)
+		}
+	}
+	return r
+}
+
+// Map converts the items of a Result, e.g. from database models to API types.
+func Map[T, U any](r Result[T], f func(T) U) Result[U] {
+	items := make([]U, len(r.Items))
+	for i := range r.Items {
+		items[i] = f(r.Items[i])
+	}
+	return Result[U]{
+		Items:         items,
+		Total:         r.Total,
+		PageNum:       r.PageNum,
+		PageSize:      r.PageSize,
+		LastPage:      r.LastPage,
+		NextPageToken: r.NextPageToken,
+		HasNext:       r.HasNext,
+	}
+}
+
+// Proto returns the pagination part of the Result as a protobuf response.
+func (r Result[T]) Proto() *PaginationResponse {
+	return &PaginationResponse{
+		Total:         r.Total,
+		PageNum:       r.PageNum,
+		LastPage:      r.LastPage,
+		PageSize:      r.PageSize,
+		NextPageToken: r.NextPageToken,
+		HasNext:       r.HasNext,
+		TotalPages:    r.LastPage,
+	}
+}
+
+// MarshalProtoJSON encodes the Result in the protobuf JSON mapping of
+// PaginationResponse, with lowerCamelCase names, strings for 64-bit integers
+// and zero values left out. Items are encoded with encoding/json.
+func (r Result[T]) MarshalProtoJSON() ([]byte, error) {
+	data, err := protojson.Marshal(r.Proto())
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	items := r.Items
+	if items == nil {
+		items = []T{}
+	}
+	if fields["items"], err = json.Marshal(items); err != nil {
+		return nil, errors.Wrap(err, "items")
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON accepts the default JSON encoding of Result as well as the protobuf JSON
+// mapping, which uses lowerCamelCase names and strings for 64-bit integers.
+func (r *Result[T]) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var result Result[T]
+	lookup := func(name string) (json.RawMessage, bool) {
+		if raw, ok := fields[name]; ok {
+			return raw, true
+		}
+		raw, ok := fields[lowerCamelCase(name)]
+		return raw, ok
+	}
+	if raw, ok := lookup("items"); ok {
+		if err := json.Unmarshal(raw, &result.Items); err != nil {
+			return errors.Wrap(err, "items")
+		}
+	}
+	for name, dst := range map[string]*int64{
+		"total":     &result.Total,
+		"page_num":  &result.PageNum,
+		"page_size": &result.PageSize,
+		"last_page": &result.LastPage,
+	} {
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		n, err := unmarshalInt64(raw)
+		if err != nil {
+			return errors.Wrap(err, name)
+		}
+		*dst = n
+	}
+	if raw, ok := lookup("next_page_token"); ok {
+		if err := json.Unmarshal(raw, &result.NextPageToken); err != nil {
+			return errors.Wrap(err, "next_page_token")
+		}
+	}
+	if raw, ok := lookup("has_next"); ok {
+		if err := json.Unmarshal(raw, &result.HasNext); err != nil {
+			return errors.Wrap(err, "has_next")
+		}
+	}
+	*r = result
+	return nil
+}
+
+// unmarshalInt64 decodes a JSON number or a string holding one, null is 0.
+func unmarshalInt64(raw json.RawMessage) (int64, error) {
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return 0, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strconv.ParseInt(s, 10, 64)
+	}
+	var n int64
+	err := json.Unmarshal(raw, &n)
+	return n, err
+}
+
+// lowerCamelCase converts a snake_case name, e.g. page_num to pageNum.
+func lowerCamelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
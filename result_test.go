@@ -0,0 +1,92 @@
+package pagination
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewResult(t *testing.T) {
+	page, err := Parse(&testRequest{PageNum: 2, PageSize: 10})
+	assert.NoError(t, err)
+	page.SetTotal(25)
+	page.NextToken = "next"
+
+	r := NewResult(page, []int{11, 12})
+	assert.Equal(t, Result[int]{
+		Items:         []int{11, 12},
+		Total:         25,
+		PageNum:       2,
+		PageSize:      10,
+		LastPage:      3,
+		NextPageToken: "next",
+		HasNext:       true,
+	}, r)
+
+	// the envelope is filled the same way as a response struct
+	filled := &Result[int]{Items: []int{11, 12}}
+	assert.NoError(t, page.FillResponse(filled))
+	assert.Equal(t, r, *filled)
+
+	mapped := Map(r, strconv.Itoa)
+	assert.Equal(t, []string{"11", "12"}, mapped.Items)
+	assert.Equal(t, r.Total, mapped.Total)
+	assert.Equal(t, r.NextPageToken, mapped.NextPageToken)
+
+	assert.Equal(t, []string{}, NewResult[string](Page{}, nil).Items)
+}
+
+func TestResult_JSON(t *testing.T) {
+	r := Result[string]{
+		Items:    []string{"a", "b"},
+		Total:    25,
+		PageNum:  2,
+		PageSize: 10,
+		LastPage: 3,
+		HasNext:  true,
+	}
+
+	data, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"items":["a","b"],"total":25,"page_num":2,"page_size":10,"last_page":3,"has_next":true}`, string(data))
+
+	var decoded Result[string]
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, r, decoded)
+
+	// protobuf JSON mapping of the pagination part
+	data, err = protojson.Marshal(r.Proto())
+	assert.NoError(t, err)
+	decoded = Result[string]{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, Result[string]{Total: 25, PageNum: 2, PageSize: 10, LastPage: 3, HasNext: true}, decoded)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"pageNum":"2","nextPageToken":"t","hasNext":true}`), &decoded))
+	assert.Equal(t, Result[string]{PageNum: 2, NextPageToken: "t", HasNext: true}, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"total":"many"}`), &decoded))
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"total":null,"pageNum":"2"}`), &decoded))
+	assert.Equal(t, Result[string]{PageNum: 2}, decoded)
+
+	// protobuf JSON mapping round trip
+	r.NextPageToken = "next"
+	data, err = r.MarshalProtoJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"items":["a","b"],"total":"25","pageNum":"2","pageSize":"10","lastPage":"3",
+		"nextPageToken":"next","hasNext":true,"totalPages":"3"}`, string(data))
+	decoded = Result[string]{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, r, decoded)
+	resp := &PaginationResponse{}
+	assert.NoError(t, protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, resp))
+	assert.True(t, proto.Equal(r.Proto(), resp))
+
+	data, err = Result[string]{}.MarshalProtoJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"items":[]}`, string(data))
+}
@@ -0,0 +1,235 @@
+package pagination
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+type sliceConfig[T any] struct {
+	tag   string
+	keys  map[string]func(T) interface{}
+	match func(item T, query string) bool
+}
+
+type SliceOption[T any] func(*sliceConfig[T])
+
+// WithSortTag resolves the fields in OrderBy by the given struct tag, e.g. "json".
+func WithSortTag[T any](tag string) SliceOption[T] {
+	return func(c *sliceConfig[T]) {
+		c.tag = tag
+	}
+}
+
+// WithSortKey sorts by the value returned by key when OrderBy contains field.
+func WithSortKey[T any](field string, key func(T) interface{}) SliceOption[T] {
+	return func(c *sliceConfig[T]) {
+		if c.keys == nil {
+			c.keys = make(map[string]func(T) interface{})
+		}
+		c.keys[field] = key
+	}
+}
+
+// WithMatcher filters the items by Query, items are not filtered without a matcher.
+func WithMatcher[T any](match func(item T, query string) bool) SliceOption[T] {
+	return func(c *sliceConfig[T]) {
+		c.match = match
+	}
+}
+
+// PaginateSlice filters, sorts and slices items in memory and returns the
+// page with Total set. OrderBy fields are resolved by WithSortKey, the
+// WithSortTag struct tag or the field name; unknown fields are not sorted by.
+// The given slice is not modified.
+func PaginateSlice[T any](items []T, p Page, options ...SliceOption[T]) ([]T, Page) {
+	var c sliceConfig[T]
+	for i := range options {
+		options[i](&c)
+	}
+
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if c.match != nil && p.Query != "" && !c.match(item, p.Query) {
+			continue
+		}
+		result = append(result, item)
+	}
+
+	if keys, err := p.SortKeys(); err == nil && len(keys) > 0 {
+		sortSlice(result, keys, &c)
+	}
+
+	p.SetTotal(len(result))
+	offset, limit := p.Offset64(), p.limit()
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 || offset >= int64(len(result)) {
+		return result[:0], p
+	}
+	result = result[offset:]
+	if limit > 0 && limit < int64(len(result)) {
+		result = result[:limit]
+	}
+	return result, p
+}
+
+type sliceSortKey[T any] struct {
+	SortKey
+	value func(T) (reflect.Value, bool)
+}
+
+func sortSlice[T any](items []T, keys []SortKey, c *sliceConfig[T]) {
+	less := sliceLess(keys, c)
+	if less == nil {
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return less(items[i], items[j])
+	})
+}
+
+// sliceLess returns the order of the items by keys, or nil when none of the
+// keys could be resolved.
+func sliceLess[T any](keys []SortKey, c *sliceConfig[T]) func(a, b T) bool {
+	var resolved []sliceSortKey[T]
+	for _, key := range keys {
+		if f, ok := c.keys[key.Field]; ok {
+			resolved = append(resolved, sliceSortKey[T]{key, func(item T) (reflect.Value, bool) {
+				return reflect.ValueOf(f(item)), true
+			}})
+			continue
+		}
+		if index, ok := sliceFieldIndex(reflect.TypeOf((*T)(nil)).Elem(), key.Field, c.tag); ok {
+			resolved = append(resolved, sliceSortKey[T]{key, func(item T) (reflect.Value, bool) {
+				v := reflect.ValueOf(&item).Elem()
+				for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+					if v.IsNil() {
+						return v, false
+					}
+					v = v.Elem()
+				}
+				return v.Field(index), true
+			}})
+		}
+	}
+	if len(resolved) == 0 {
+		return nil
+	}
+
+	return func(x, y T) bool {
+		for _, key := range resolved {
+			a, aok := key.value(x)
+			b, bok := key.value(y)
+			aNil, bNil := isNil(a, aok), isNil(b, bok)
+			if aNil || bNil {
+				if aNil && bNil {
+					continue
+				}
+				// nil is the smallest value unless the key says otherwise
+				nilFirst := key.Nulls == NullsFirst || key.Nulls == NullsDefault && !key.Descending
+				return aNil == nilFirst
+			}
+
+			c := compareValues(a, b)
+			if c == 0 {
+				continue
+			}
+			if key.Descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	}
+}
+
+func sliceFieldIndex(t reflect.Type, name, tag string) (int, bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return 0, false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if tag != "" {
+			if tagName := strings.Split(f.Tag.Get(tag), ",")[0]; tagName == name {
+				return i, true
+			}
+		}
+		if f.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func isNil(v reflect.Value, ok bool) bool {
+	if !ok || !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// compareValues compares two non-nil sort values, values of different
+// or unsupported types are equal.
+func compareValues(a, b reflect.Value) int {
+	for a.Kind() == reflect.Ptr || a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	for b.Kind() == reflect.Ptr || b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+
+	if at, ok := a.Interface().(time.Time); ok {
+		if bt, ok := b.Interface().(time.Time); ok {
+			switch {
+			case at.Before(bt):
+				return -1
+			case at.After(bt):
+				return 1
+			}
+			return 0
+		}
+	}
+	switch {
+	case a.CanInt() && b.CanInt():
+		return compareOrdered(a.Int(), b.Int())
+	case a.CanUint() && b.CanUint():
+		return compareOrdered(a.Uint(), b.Uint())
+	case a.CanFloat() && b.CanFloat():
+		return compareOrdered(a.Float(), b.Float())
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		return compareOrdered(boolInt(a.Bool()), boolInt(b.Bool()))
+	}
+	return 0
+}
+
+func compareOrdered[N int64 | uint64 | float64 | int](a, b N) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,130 @@
+package pagination
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sliceItem struct {
+	ID    int     `json:"id"`
+	Name  string  `json:"name"`
+	Score *int    `json:"score"`
+	Rate  float64 `json:"rate"`
+}
+
+func TestPaginateSlice(t *testing.T) {
+	one, two := 1, 2
+	items := []sliceItem{
+		{ID: 1, Name: "carol", Score: &two, Rate: 0.5},
+		{ID: 2, Name: "alice", Score: nil, Rate: 0.1},
+		{ID: 3, Name: "bob", Score: &one, Rate: 0.5},
+		{ID: 4, Name: "dave", Score: &two, Rate: 0.9},
+		{ID: 5, Name: "alex", Score: nil, Rate: 0.3},
+	}
+	ids := func(items []sliceItem) []int {
+		var ids []int
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name     string
+		page     Page
+		options  []SliceOption[sliceItem]
+		excepted []int
+		total    int
+	}{
+		{
+			name:     "no pagination",
+			page:     Page{},
+			excepted: []int{1, 2, 3, 4, 5},
+			total:    5,
+		},
+		{
+			name:     "by field name",
+			page:     Page{Num: 1, Size: 2, OrderBy: "Name"},
+			excepted: []int{5, 2},
+			total:    5,
+		},
+		{
+			name:     "descending second page",
+			page:     Page{Num: 2, Size: 2, OrderBy: "Name", IsDescending: true},
+			excepted: []int{3, 2},
+			total:    5,
+		},
+		{
+			name:     "by struct tag with nil pointers",
+			page:     Page{Num: 1, Size: 10, OrderBy: "score, id desc"},
+			options:  []SliceOption[sliceItem]{WithSortTag[sliceItem]("json")},
+			excepted: []int{5, 2, 3, 4, 1},
+			total:    5,
+		},
+		{
+			name:     "nulls last",
+			page:     Page{Num: 1, Size: 10, OrderBy: "score desc nulls last, id"},
+			options:  []SliceOption[sliceItem]{WithSortTag[sliceItem]("json")},
+			excepted: []int{1, 4, 3, 2, 5},
+			total:    5,
+		},
+		{
+			name: "by key function",
+			page: Page{Num: 1, Size: 3, OrderBy: "length, Rate desc"},
+			options: []SliceOption[sliceItem]{WithSortKey("length", func(item sliceItem) interface{} {
+				return len(item.Name)
+			})},
+			excepted: []int{3, 4, 5},
+			total:    5,
+		},
+		{
+			name: "filtered",
+			page: Page{Num: 1, Size: 10, OrderBy: "ID", IsDescending: true, Query: "al"},
+			options: []SliceOption[sliceItem]{WithMatcher(func(item sliceItem, query string) bool {
+				return strings.Contains(item.Name, query)
+			})},
+			excepted: []int{5, 2},
+			total:    2,
+		},
+		{
+			name:     "beyond the end",
+			page:     Page{Num: 4, Size: 2},
+			excepted: nil,
+			total:    5,
+		},
+		{
+			name:     "negative size",
+			page:     Page{Num: 3, Size: -1},
+			excepted: nil,
+			total:    5,
+		},
+		{
+			name:     "negative page number",
+			page:     Page{Num: -2, Size: 2},
+			excepted: []int{1, 2},
+			total:    5,
+		},
+		{
+			name:     "unknown field",
+			page:     Page{Num: 1, Size: 2, OrderBy: "unknown"},
+			excepted: []int{1, 2},
+			total:    5,
+		},
+	}
+	for _, test := range tests {
+		result, page := PaginateSlice(items, test.page, test.options...)
+		assert.Equal(t, test.excepted, ids(result), test.name)
+		assert.Equal(t, test.total, page.Total, test.name)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids(items))
+
+	page, err := Parse(&struct{ PageNum, PageSize int }{3, -1})
+	assert.NoError(t, err)
+	result, _ := PaginateSlice(items, page)
+	assert.Empty(t, result)
+
+	ptrs, _ := PaginateSlice([]*sliceItem{&items[0], nil, &items[1]}, Page{OrderBy: "ID", IsDescending: true})
+	assert.Equal(t, []*sliceItem{&items[1], &items[0], nil}, ptrs)
+}
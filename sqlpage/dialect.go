@@ -0,0 +1,282 @@
+package sqlpage
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.github.com/uptutu/pagination"
+)
+
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+var _identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Dialect renders the parts of a query which differ between databases.
+type Dialect interface {
+	Name() string
+	// Placeholder returns the n-th bind parameter, starting at 1.
+	Placeholder(n int) string
+	// QuoteIdent quotes a single, already validated identifier.
+	QuoteIdent(ident string) string
+	// OrderTerm renders one term of an ORDER BY clause for a quoted column.
+	OrderTerm(column string, key pagination.SortKey) string
+	// Paginate renders the LIMIT/OFFSET clause. n is the index of its first
+	// placeholder. A zero limit means no limit.
+	Paginate(limit, offset int64, n int) (string, []interface{})
+	// NullsLargest reports whether NULL sorts after all values in ascending order.
+	NullsLargest() bool
+	// RowValues reports whether row values can be compared, e.g. (a, b) > (?, ?).
+	RowValues() bool
+}
+
+var (
+	MySQL     Dialect = mysql{}
+	Postgres  Dialect = postgres{}
+	SQLite    Dialect = sqlite{}
+	SQLServer Dialect = sqlServer{}
+	Oracle    Dialect = oracle{}
+)
+
+// Quote validates and quotes a column name, which may be qualified by a table
+// name, e.g. "u.created_at".
+func Quote(d Dialect, name string) (string, error) {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if !_identifier.MatchString(part) {
+			return "", errors.Wrapf(ErrInvalidIdentifier, "%q", name)
+		}
+		parts[i] = d.QuoteIdent(part)
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// OrderBy renders the ORDER BY clause of p, or "" when p has no order.
+func OrderBy(d Dialect, p pagination.Page) (string, error) {
+	keys, err := p.SortKeys()
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		return "", nil
+	}
+
+	terms := make([]string, 0, len(keys))
+	for _, key := range keys {
+		column, err := Quote(d, key.Field)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, d.OrderTerm(column, key))
+	}
+	return "ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+// Paginate renders the LIMIT/OFFSET clause of p, n is the index of its first placeholder.
+func Paginate(d Dialect, p pagination.Page, n int) (string, []interface{}) {
+	return d.Paginate(p.Limit64(), p.Offset64(), n)
+}
+
+// fallbackOrderer is implemented by dialects which can't paginate an unordered query.
+type fallbackOrderer interface {
+	FallbackOrderBy() string
+}
+
+// Render renders the ORDER BY and the LIMIT/OFFSET clauses of p together.
+func Render(d Dialect, p pagination.Page, n int) (string, []interface{}, error) {
+	orderBy, err := OrderBy(d, p)
+	if err != nil {
+		return "", nil, err
+	}
+	paginate, args := Paginate(d, p, n)
+	if orderBy == "" && paginate != "" {
+		if f, ok := d.(fallbackOrderer); ok {
+			orderBy = f.FallbackOrderBy()
+		}
+	}
+	return strings.TrimSpace(orderBy + " " + paginate), args, nil
+}
+
+func direction(key pagination.SortKey) string {
+	if key.Descending {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// standardOrderTerm supports NULLS FIRST and NULLS LAST.
+func standardOrderTerm(column string, key pagination.SortKey) string {
+	term := column + " " + direction(key)
+	switch key.Nulls {
+	case pagination.NullsFirst:
+		term += " NULLS FIRST"
+	case pagination.NullsLast:
+		term += " NULLS LAST"
+	}
+	return term
+}
+
+type mysql struct{}
+
+func (mysql) Name() string { return "mysql" }
+
+func (mysql) Placeholder(int) string { return "?" }
+
+func (mysql) NullsLargest() bool { return false }
+
+func (mysql) RowValues() bool { return true }
+
+func (mysql) QuoteIdent(ident string) string {
+	return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+}
+
+func (mysql) OrderTerm(column string, key pagination.SortKey) string {
+	term := column + " " + direction(key)
+	switch key.Nulls {
+	case pagination.NullsFirst:
+		term = column + " IS NULL DESC, " + term
+	case pagination.NullsLast:
+		term = column + " IS NULL ASC, " + term
+	}
+	return term
+}
+
+func (mysql) Paginate(limit, offset int64, _ int) (string, []interface{}) {
+	switch {
+	case limit > 0:
+		return "LIMIT ? OFFSET ?", []interface{}{limit, offset}
+	case offset > 0:
+		// MySQL has no OFFSET without LIMIT
+		return "LIMIT 18446744073709551615 OFFSET ?", []interface{}{offset}
+	}
+	return "", nil
+}
+
+type postgres struct{}
+
+func (postgres) Name() string { return "postgres" }
+
+func (postgres) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+func (postgres) NullsLargest() bool { return true }
+
+func (postgres) RowValues() bool { return true }
+
+func (postgres) QuoteIdent(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+func (postgres) OrderTerm(column string, key pagination.SortKey) string {
+	return standardOrderTerm(column, key)
+}
+
+func (d postgres) Paginate(limit, offset int64, n int) (string, []interface{}) {
+	switch {
+	case limit > 0:
+		return "LIMIT " + d.Placeholder(n) + " OFFSET " + d.Placeholder(n+1), []interface{}{limit, offset}
+	case offset > 0:
+		return "OFFSET " + d.Placeholder(n), []interface{}{offset}
+	}
+	return "", nil
+}
+
+type sqlite struct{}
+
+func (sqlite) Name() string { return "sqlite" }
+
+func (sqlite) Placeholder(int) string { return "?" }
+
+func (sqlite) NullsLargest() bool { return false }
+
+// RowValues needs SQLite 3.15.0 or later.
+func (sqlite) RowValues() bool { return true }
+
+func (sqlite) QuoteIdent(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// OrderTerm uses NULLS FIRST and NULLS LAST, which need SQLite 3.30.0 or later.
+func (sqlite) OrderTerm(column string, key pagination.SortKey) string {
+	return standardOrderTerm(column, key)
+}
+
+func (sqlite) Paginate(limit, offset int64, _ int) (string, []interface{}) {
+	switch {
+	case limit > 0:
+		return "LIMIT ? OFFSET ?", []interface{}{limit, offset}
+	case offset > 0:
+		return "LIMIT -1 OFFSET ?", []interface{}{offset}
+	}
+	return "", nil
+}
+
+type sqlServer struct{}
+
+func (sqlServer) Name() string { return "sqlserver" }
+
+func (sqlServer) Placeholder(n int) string { return "@p" + strconv.Itoa(n) }
+
+func (sqlServer) NullsLargest() bool { return false }
+
+func (sqlServer) RowValues() bool { return false }
+
+func (sqlServer) QuoteIdent(ident string) string {
+	return "[" + strings.ReplaceAll(ident, "]", "]]") + "]"
+}
+
+func (sqlServer) OrderTerm(column string, key pagination.SortKey) string {
+	term := column + " " + direction(key)
+	switch key.Nulls {
+	case pagination.NullsFirst:
+		term = "CASE WHEN " + column + " IS NULL THEN 0 ELSE 1 END, " + term
+	case pagination.NullsLast:
+		term = "CASE WHEN " + column + " IS NULL THEN 1 ELSE 0 END, " + term
+	}
+	return term
+}
+
+// Paginate needs an ORDER BY in the query, see Render.
+func (d sqlServer) Paginate(limit, offset int64, n int) (string, []interface{}) {
+	switch {
+	case limit > 0:
+		return "OFFSET " + d.Placeholder(n) + " ROWS FETCH NEXT " + d.Placeholder(n+1) + " ROWS ONLY", []interface{}{offset, limit}
+	case offset > 0:
+		return "OFFSET " + d.Placeholder(n) + " ROWS", []interface{}{offset}
+	}
+	return "", nil
+}
+
+func (sqlServer) FallbackOrderBy() string { return "ORDER BY (SELECT NULL)" }
+
+type oracle struct{}
+
+func (oracle) Name() string { return "oracle" }
+
+func (oracle) Placeholder(n int) string { return ":" + strconv.Itoa(n) }
+
+func (oracle) NullsLargest() bool { return true }
+
+func (oracle) RowValues() bool { return false }
+
+func (oracle) QuoteIdent(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+func (oracle) OrderTerm(column string, key pagination.SortKey) string {
+	return standardOrderTerm(column, key)
+}
+
+// Paginate uses the row limiting clause of Oracle 12c.
+func (d oracle) Paginate(limit, offset int64, n int) (string, []interface{}) {
+	switch {
+	case limit > 0 && offset > 0:
+		return "OFFSET " + d.Placeholder(n) + " ROWS FETCH NEXT " + d.Placeholder(n+1) + " ROWS ONLY", []interface{}{offset, limit}
+	case limit > 0:
+		return "FETCH FIRST " + d.Placeholder(n) + " ROWS ONLY", []interface{}{limit}
+	case offset > 0:
+		return "OFFSET " + d.Placeholder(n) + " ROWS", []interface{}{offset}
+	}
+	return "", nil
+}
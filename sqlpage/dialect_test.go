@@ -0,0 +1,84 @@
+package sqlpage
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.github.com/uptutu/pagination"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var _dialectPages = []struct {
+	name string
+	page pagination.Page
+	n    int
+}{
+	{name: "no pagination", page: pagination.Page{}, n: 1},
+	{name: "first page", page: pagination.Page{Num: 1, Size: 10}, n: 1},
+	{name: "third page after two args", page: pagination.Page{Num: 3, Size: 20}, n: 3},
+	{name: "ordered", page: pagination.Page{Num: 2, Size: 10, OrderBy: "name"}, n: 1},
+	{name: "ordered descending", page: pagination.Page{Num: 2, Size: 10, OrderBy: "name, id asc", IsDescending: true}, n: 1},
+	{name: "qualified with nulls", page: pagination.Page{Num: 2, Size: 10, OrderBy: "u.created_at desc nulls last, u.score nulls first"}, n: 1},
+}
+
+func TestDialectGolden(t *testing.T) {
+	for _, d := range []Dialect{MySQL, Postgres, SQLite, SQLServer, Oracle} {
+		var b strings.Builder
+		for _, test := range _dialectPages {
+			clause, args, err := Render(d, test.page, test.n)
+			require.NoError(t, err)
+			fmt.Fprintf(&b, "-- %s\n%s\n%v\n", test.name, clause, args)
+		}
+
+		golden := filepath.Join("testdata", "dialect_"+d.Name()+".golden")
+		if *update {
+			require.NoError(t, os.WriteFile(golden, []byte(b.String()), 0o644))
+		}
+		want, err := os.ReadFile(golden)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), b.String(), d.Name())
+	}
+}
+
+func TestOrderByInvalid(t *testing.T) {
+	for _, orderBy := range []string{"name; DROP TABLE users", "1id", "name)--", "a..b", "nulls middle"} {
+		_, err := OrderBy(Postgres, pagination.Page{OrderBy: orderBy})
+		assert.Error(t, err, orderBy)
+	}
+
+	quoted, err := Quote(MySQL, "users.name")
+	assert.NoError(t, err)
+	assert.Equal(t, "`users`.`name`", quoted)
+}
+
+func TestQueryWithOrderBy(t *testing.T) {
+	db := openDB(t)
+	for _, options := range [][]Option{
+		{WithDialect(SQLite), WithOrderBy()},
+		{WithDialect(SQLite), WithOrderBy(), WithWindowCount()},
+	} {
+		var ids []int
+		page, err := Query(context.Background(), db, pagination.Page{Num: 2, Size: 5, OrderBy: "id", IsDescending: true},
+			"SELECT id FROM users", nil,
+			func(s Scanner) error {
+				var id int
+				if err := s.Scan(&id); err != nil {
+					return err
+				}
+				ids = append(ids, id)
+				return nil
+			}, options...)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{18, 17, 16, 15, 14}, ids)
+		assert.Equal(t, 23, page.Total)
+	}
+}
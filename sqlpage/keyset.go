@@ -0,0 +1,138 @@
+package sqlpage
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.github.com/uptutu/pagination"
+)
+
+var ErrSeekValues = errors.New("seek values don't match the sort keys")
+
+// SeekPage renders the keyset predicate of the page order, see Seek.
+func SeekPage(d Dialect, p pagination.Page, values []interface{}, n int) (string, []interface{}, error) {
+	keys, err := p.SortKeys()
+	if err != nil {
+		return "", nil, err
+	}
+	return Seek(d, keys, values, n)
+}
+
+// Seek renders the WHERE predicate selecting the rows sorted after the row
+// with the given values of the sort keys, which usually come from a decoded
+// cursor. n is the index of the first placeholder.
+//
+// NULL values sorted after a value, by Nulls or by the default of the dialect,
+// are matched by an IS NULL condition. The keys should end with a unique
+// column so that rows with equal values are not skipped.
+func Seek(d Dialect, keys []pagination.SortKey, values []interface{}, n int) (string, []interface{}, error) {
+	if len(keys) == 0 || len(keys) != len(values) {
+		return "", nil, errors.Wrapf(ErrSeekValues, "%d keys, %d values", len(keys), len(values))
+	}
+
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		column, err := Quote(d, key.Field)
+		if err != nil {
+			return "", nil, err
+		}
+		columns[i] = column
+	}
+
+	s := seeker{d: d, n: n}
+	if len(keys) > 1 && rowComparable(d, keys, values) {
+		placeholders := make([]string, len(values))
+		for i := range values {
+			placeholders[i] = s.bind(values[i])
+		}
+		return "(" + strings.Join(columns, ", ") + ") " + comparison(keys[0]) + " (" + strings.Join(placeholders, ", ") + ")", s.args, nil
+	}
+
+	var terms []string
+	for i, key := range keys {
+		if values[i] == nil && nullsLast(d, key) {
+			// nothing sorts after NULL
+			continue
+		}
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, s.equal(columns[j], values[j]))
+		}
+		conds = append(conds, s.after(columns[i], key, values[i]))
+		if len(conds) == 1 {
+			terms = append(terms, conds[0])
+			continue
+		}
+		terms = append(terms, "("+strings.Join(conds, " AND ")+")")
+	}
+	if len(terms) == 0 {
+		return "1 = 0", nil, nil
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", s.args, nil
+}
+
+// rowComparable reports whether the predicate can be a single row value comparison.
+func rowComparable(d Dialect, keys []pagination.SortKey, values []interface{}) bool {
+	if !d.RowValues() {
+		return false
+	}
+	for i, key := range keys {
+		// NULL values sorted after the row don't compare as greater
+		if key.Descending != keys[0].Descending || key.Nulls != pagination.NullsDefault || values[i] == nil || nullsLast(d, key) {
+			return false
+		}
+	}
+	return true
+}
+
+func comparison(key pagination.SortKey) string {
+	if key.Descending {
+		return "<"
+	}
+	return ">"
+}
+
+// nullsLast reports whether NULL values of the key come after the other values.
+func nullsLast(d Dialect, key pagination.SortKey) bool {
+	switch key.Nulls {
+	case pagination.NullsFirst:
+		return false
+	case pagination.NullsLast:
+		return true
+	}
+	return d.NullsLargest() != key.Descending
+}
+
+type seeker struct {
+	d    Dialect
+	n    int
+	args []interface{}
+}
+
+func (s *seeker) bind(value interface{}) string {
+	placeholder := s.d.Placeholder(s.n + len(s.args))
+	s.args = append(s.args, value)
+	return placeholder
+}
+
+func (s *seeker) equal(column string, value interface{}) string {
+	if value == nil {
+		return column + " IS NULL"
+	}
+	return column + " = " + s.bind(value)
+}
+
+// after renders the condition of the rows sorted strictly after value in
+// column. A nil value must sort before NULL values.
+func (s *seeker) after(column string, key pagination.SortKey, value interface{}) string {
+	if value == nil {
+		return column + " IS NOT NULL"
+	}
+
+	cond := column + " " + comparison(key) + " " + s.bind(value)
+	if nullsLast(s.d, key) {
+		return "(" + cond + " OR " + column + " IS NULL)"
+	}
+	return cond
+}
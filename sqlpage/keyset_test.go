@@ -0,0 +1,200 @@
+package sqlpage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.github.com/uptutu/pagination"
+)
+
+func TestSeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		orderBy  string
+		values   []interface{}
+		n        int
+		excepted string
+		args     []interface{}
+	}{
+		{
+			name:     "single column",
+			dialect:  MySQL,
+			orderBy:  "id",
+			values:   []interface{}{5},
+			n:        1,
+			excepted: "(`id` > ?)",
+			args:     []interface{}{5},
+		},
+		{
+			name:     "row values",
+			dialect:  Postgres,
+			orderBy:  "created_at desc, id desc",
+			values:   []interface{}{"2022-05-07", 5},
+			n:        2,
+			excepted: `("created_at", "id") < ($2, $3)`,
+			args:     []interface{}{"2022-05-07", 5},
+		},
+		{
+			name:     "mixed directions",
+			dialect:  Postgres,
+			orderBy:  "created_at desc, id",
+			values:   []interface{}{"2022-05-07", 5},
+			n:        1,
+			excepted: `("created_at" < $1 OR ("created_at" = $2 AND ("id" > $3 OR "id" IS NULL)))`,
+			args:     []interface{}{"2022-05-07", "2022-05-07", 5},
+		},
+		{
+			name:     "nullable last",
+			dialect:  MySQL,
+			orderBy:  "score nulls last, id",
+			values:   []interface{}{10, 5},
+			n:        1,
+			excepted: "((`score` > ? OR `score` IS NULL) OR (`score` = ? AND `id` > ?))",
+			args:     []interface{}{10, 10, 5},
+		},
+		{
+			name:     "nulls last by default",
+			dialect:  Postgres,
+			orderBy:  "score, id desc",
+			values:   []interface{}{10, 5},
+			n:        1,
+			excepted: `(("score" > $1 OR "score" IS NULL) OR ("score" = $2 AND "id" < $3))`,
+			args:     []interface{}{10, 10, 5},
+		},
+		{
+			name:     "nulls last by default descending",
+			dialect:  MySQL,
+			orderBy:  "score desc, id desc",
+			values:   []interface{}{10, 5},
+			n:        1,
+			excepted: "((`score` < ? OR `score` IS NULL) OR (`score` = ? AND (`id` < ? OR `id` IS NULL)))",
+			args:     []interface{}{10, 10, 5},
+		},
+		{
+			name:     "null value sorted last",
+			dialect:  Postgres,
+			orderBy:  "score, id",
+			values:   []interface{}{nil, 5},
+			n:        1,
+			excepted: `(("score" IS NULL AND ("id" > $1 OR "id" IS NULL)))`,
+			args:     []interface{}{5},
+		},
+		{
+			name:     "null value sorted first",
+			dialect:  SQLite,
+			orderBy:  "score, id",
+			values:   []interface{}{nil, 5},
+			n:        1,
+			excepted: `("score" IS NOT NULL OR ("score" IS NULL AND "id" > ?))`,
+			args:     []interface{}{5},
+		},
+		{
+			name:     "no rows after",
+			dialect:  Postgres,
+			orderBy:  "score",
+			values:   []interface{}{nil},
+			n:        1,
+			excepted: "1 = 0",
+		},
+	}
+	for _, test := range tests {
+		where, args, err := SeekPage(test.dialect, pagination.Page{OrderBy: test.orderBy}, test.values, test.n)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.excepted, where, test.name)
+		assert.Equal(t, test.args, args, test.name)
+	}
+
+	_, _, err := SeekPage(MySQL, pagination.Page{OrderBy: "a, b"}, []interface{}{1}, 1)
+	assert.ErrorIs(t, err, ErrSeekValues)
+	_, _, err = SeekPage(MySQL, pagination.Page{OrderBy: "a;b"}, []interface{}{1}, 1)
+	assert.ErrorIs(t, err, ErrInvalidIdentifier)
+}
+
+// TestSeekWalk walks a table with keyset pagination and compares the result
+// with the plain ORDER BY of the whole table.
+func TestSeekWalk(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, grp INTEGER NOT NULL, score INTEGER)")
+	require.NoError(t, err)
+	for i := 1; i <= 40; i++ {
+		var score interface{}
+		if i%4 != 0 {
+			score = i % 7
+		}
+		_, err = db.Exec("INSERT INTO items (id, grp, score) VALUES (?, ?, ?)", i, i%3, score)
+		require.NoError(t, err)
+	}
+
+	ctx := context.Background()
+	for _, orderBy := range []string{
+		"grp, id",
+		"grp desc, id desc",
+		"grp desc, id",
+		"score nulls first, id",
+		"score nulls last, id desc",
+		"score desc nulls first, grp, id",
+		"score desc nulls last, grp desc, id",
+		"score, id",
+		"score desc nulls last, id",
+		"score desc, id",
+		"score desc, grp desc, id desc",
+	} {
+		p := pagination.Page{Size: 6, OrderBy: orderBy}
+		order, err := OrderBy(SQLite, p)
+		require.NoError(t, err)
+
+		var all []int
+		_, err = Query(ctx, db, pagination.Page{}, "SELECT id FROM items "+order, nil, func(s Scanner) error {
+			var id int
+			err := s.Scan(&id)
+			all = append(all, id)
+			return err
+		})
+		require.NoError(t, err)
+
+		keys, err := p.SortKeys()
+		require.NoError(t, err)
+		columns := "id, grp, score"
+		var walked []int
+		var last []interface{}
+		for pages := 0; pages < 20; pages++ {
+			query, args := "SELECT "+columns+" FROM items", []interface{}(nil)
+			if last != nil {
+				where, whereArgs, err := Seek(SQLite, keys, last, 1)
+				require.NoError(t, err, orderBy)
+				query, args = query+" WHERE "+where, whereArgs
+			}
+			rows, err := db.Query(query+" "+order+fmt.Sprintf(" LIMIT %d", p.Size), args...)
+			require.NoError(t, err, orderBy)
+			n := 0
+			for rows.Next() {
+				var id, grp int
+				var score sql.NullInt64
+				require.NoError(t, rows.Scan(&id, &grp, &score))
+				walked = append(walked, id)
+				row := map[string]interface{}{"id": id, "grp": grp, "score": nil}
+				if score.Valid {
+					row["score"] = score.Int64
+				}
+				last = last[:0]
+				for _, key := range keys {
+					last = append(last, row[key.Field])
+				}
+				n++
+			}
+			require.NoError(t, rows.Close())
+			if n < p.Size {
+				break
+			}
+		}
+		assert.Equal(t, all, walked, orderBy)
+	}
+}
@@ -0,0 +1,278 @@
+// Package sqlpage runs the paged query and the count query of a pagination.Page
+// against database/sql.
+package sqlpage
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.github.com/uptutu/pagination"
+)
+
+var ErrTxUnsupported = errors.New("queryer can not begin transactions")
+
+// Queryer is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// TxBeginner is implemented by *sql.DB and *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Scanner is implemented by *sql.Rows.
+type Scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type config struct {
+	dialect Dialect
+	tx      bool
+	window  bool
+	orderBy bool
+}
+
+type Option func(*config)
+
+// WithDialect sets the dialect of the paged query, MySQL by default.
+func WithDialect(d Dialect) Option {
+	return func(c *config) {
+		c.dialect = d
+	}
+}
+
+// WithOrderBy appends the ORDER BY clause rendered from the page to the base
+// query, which then must not have one itself.
+func WithOrderBy() Option {
+	return func(c *config) {
+		c.orderBy = true
+	}
+}
+
+// WithReadOnlyTx runs the count and the paged query in one read-only
+// transaction, so that both see the same snapshot.
+func WithReadOnlyTx() Option {
+	return func(c *config) {
+		c.tx = true
+	}
+}
+
+// WithWindowCount counts the rows with COUNT(*) OVER() in the paged query
+// instead of running a separate count query. The base query is wrapped in a
+// derived table and its ORDER BY moved to the outer query, so it must order
+// by columns of the result, without table names.
+func WithWindowCount() Option {
+	return func(c *config) {
+		c.window = true
+	}
+}
+
+// Query runs the base query paged by p, calls scan for each row and returns p
+// with Total set. The base query must not contain LIMIT or OFFSET, its ORDER
+// BY is left out of the count query. Unordered queries get the fallback order
+// of dialects which need one, e.g. SQLServer.
+func Query(ctx context.Context, db Queryer, p pagination.Page, query string, args []interface{}, scan func(Scanner) error, options ...Option) (_ pagination.Page, err error) {
+	c := config{dialect: MySQL}
+	for i := range options {
+		options[i](&c)
+	}
+
+	if c.tx {
+		b, ok := db.(TxBeginner)
+		if !ok {
+			return p, ErrTxUnsupported
+		}
+		tx, err := b.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return p, errors.Wrap(err, "begin transaction")
+		}
+		defer func() {
+			if err != nil {
+				_ = tx.Rollback()
+				return
+			}
+			err = errors.Wrap(tx.Commit(), "commit transaction")
+		}()
+		db = tx
+	}
+
+	if c.window {
+		return queryWindow(ctx, db, c, p, query, args, scan)
+	}
+
+	total, err := count(ctx, db, query, args)
+	if err != nil {
+		return p, err
+	}
+	p.SetTotal(total)
+
+	paged, pagedArgs, err := paginate(c, p, query, args)
+	if err != nil {
+		return p, err
+	}
+	if _, err := queryRows(ctx, db, paged, pagedArgs, scan); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+func queryWindow(ctx context.Context, db Queryer, c config, p pagination.Page, query string, args []interface{}, scan func(Scanner) error) (pagination.Page, error) {
+	var total int
+	paged, pagedArgs, err := paginate(c, p, windowQuery(query), args)
+	if err != nil {
+		return p, err
+	}
+	n, err := queryRows(ctx, db, paged, pagedArgs, func(s Scanner) error {
+		return scan(totalScanner{s, &total})
+	})
+	if err != nil {
+		return p, err
+	}
+	// an empty page beyond the end carries no count
+	if n == 0 && p.Offset64() > 0 {
+		if total, err = count(ctx, db, query, args); err != nil {
+			return p, err
+		}
+	}
+	p.SetTotal(total)
+	return p, nil
+}
+
+// windowQuery wraps query to count its rows with COUNT(*) OVER(). The ORDER BY
+// of query is moved to the outer query, as the order of a derived table isn't
+// kept and SQL Server rejects it.
+func windowQuery(query string) string {
+	inner, orderBy := splitOrderBy(query)
+	paged := "SELECT paged.*, COUNT(*) OVER() FROM (" + inner + ") paged"
+	if orderBy != "" {
+		paged += " " + orderBy
+	}
+	return paged
+}
+
+// countQuery counts the rows of query, without its ORDER BY.
+func countQuery(query string) string {
+	inner, _ := splitOrderBy(query)
+	return "SELECT COUNT(*) FROM (" + inner + ") counted"
+}
+
+func count(ctx context.Context, db Queryer, query string, args []interface{}) (int, error) {
+	var total int
+	_, err := queryRows(ctx, db, countQuery(query), args, func(s Scanner) error {
+		return s.Scan(&total)
+	})
+	return total, errors.Wrap(err, "count")
+}
+
+func paginate(c config, p pagination.Page, query string, args []interface{}) (string, []interface{}, error) {
+	var clause string
+	var clauseArgs []interface{}
+	if c.orderBy {
+		var err error
+		if clause, clauseArgs, err = Render(c.dialect, p, len(args)+1); err != nil {
+			return "", nil, err
+		}
+	} else {
+		clause, clauseArgs = Paginate(c.dialect, p, len(args)+1)
+		// dialects which can't paginate an unordered query need an ORDER BY
+		if f, ok := c.dialect.(fallbackOrderer); ok && clause != "" && orderByIndex(query) < 0 {
+			clause = f.FallbackOrderBy() + " " + clause
+		}
+	}
+	if clause == "" {
+		return query, args, nil
+	}
+
+	paged := make([]interface{}, 0, len(args)+len(clauseArgs))
+	paged = append(paged, args...)
+	paged = append(paged, clauseArgs...)
+	return query + " " + clause, paged, nil
+}
+
+func queryRows(ctx context.Context, db Queryer, query string, args []interface{}, scan func(Scanner) error) (int, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "query")
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return n, errors.Wrap(err, "scan")
+		}
+		n++
+	}
+	return n, errors.Wrap(rows.Err(), "rows")
+}
+
+// totalScanner appends the window count column to the scanned columns.
+type totalScanner struct {
+	Scanner
+	total *int
+}
+
+func (s totalScanner) Scan(dest ...interface{}) error {
+	return s.Scanner.Scan(append(dest, s.total)...)
+}
+
+var _orderBy = regexp.MustCompile(`^(?i)ORDER\s+BY\b`)
+
+// orderByIndex returns the index of the ORDER BY clause of query, or -1. ORDER
+// BY in parentheses, e.g. of subqueries or window functions, in quotes and in
+// comments is skipped.
+func orderByIndex(query string) int {
+	index, depth := -1, 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(query[i+1:], closing)
+			if end < 0 {
+				return index
+			}
+			i += end + 1
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return index
+			}
+			i += end
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i:], "*/")
+			if end < 0 {
+				return index
+			}
+			i += end + 1
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && (c == 'o' || c == 'O') && (i == 0 || !isIdentByte(query[i-1])) &&
+			_orderBy.MatchString(query[i:]):
+			index = i
+		}
+	}
+	return index
+}
+
+// splitOrderBy splits the ORDER BY clause off query.
+func splitOrderBy(query string) (string, string) {
+	i := orderByIndex(query)
+	if i < 0 {
+		return query, ""
+	}
+	return strings.TrimSpace(query[:i]), strings.TrimSpace(query[i:])
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,195 @@
+package sqlpage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.github.com/uptutu/pagination"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	require.NoError(t, err)
+	for i := 1; i <= 23; i++ {
+		_, err = db.Exec("INSERT INTO users (id, name) VALUES (?, ?)", i, fmt.Sprintf("user%02d", i))
+		require.NoError(t, err)
+	}
+	return db
+}
+
+func TestQuery(t *testing.T) {
+	db := openDB(t)
+	tests := []struct {
+		name     string
+		page     pagination.Page
+		options  []Option
+		excepted []int
+		total    int
+	}{
+		{
+			name:     "first page",
+			page:     pagination.Page{Num: 1, Size: 10},
+			excepted: []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			total:    22,
+		},
+		{
+			name:     "last page",
+			page:     pagination.Page{Num: 3, Size: 10},
+			excepted: []int{22, 23},
+			total:    22,
+		},
+		{
+			name:     "beyond the end",
+			page:     pagination.Page{Num: 4, Size: 10},
+			excepted: nil,
+			total:    22,
+		},
+		{
+			name:     "no pagination",
+			page:     pagination.Page{},
+			excepted: []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
+			total:    22,
+		},
+		{
+			name:     "read-only transaction",
+			page:     pagination.Page{Num: 3, Size: 10},
+			options:  []Option{WithReadOnlyTx()},
+			excepted: []int{22, 23},
+			total:    22,
+		},
+		{
+			name:     "window count",
+			page:     pagination.Page{Num: 2, Size: 10},
+			options:  []Option{WithWindowCount()},
+			excepted: []int{12, 13, 14, 15, 16, 17, 18, 19, 20, 21},
+			total:    22,
+		},
+		{
+			name:     "window count beyond the end",
+			page:     pagination.Page{Num: 4, Size: 10},
+			options:  []Option{WithWindowCount(), WithReadOnlyTx()},
+			excepted: nil,
+			total:    22,
+		},
+	}
+
+	for _, test := range tests {
+		var ids []int
+		page, err := Query(context.Background(), db, test.page,
+			"SELECT id, name FROM users WHERE id > ? ORDER BY id", []interface{}{1},
+			func(s Scanner) error {
+				var id int
+				var name string
+				if err := s.Scan(&id, &name); err != nil {
+					return err
+				}
+				ids = append(ids, id)
+				return nil
+			}, test.options...)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.excepted, ids, test.name)
+		assert.Equal(t, test.total, page.Total, test.name)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	db := openDB(t)
+	ctx := context.Background()
+	noop := func(Scanner) error { return nil }
+
+	_, err := Query(ctx, db, pagination.Page{Num: 1, Size: 10}, "SELECT nope FROM users", nil, noop)
+	assert.Error(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+	_, err = Query(ctx, tx, pagination.Page{Num: 1, Size: 10}, "SELECT id FROM users", nil, noop, WithReadOnlyTx())
+	assert.Equal(t, ErrTxUnsupported, err)
+}
+
+func TestQuerySQL(t *testing.T) {
+	page := pagination.Page{Num: 2, Size: 10, OrderBy: "id", IsDescending: true}
+	tests := []struct {
+		name    string
+		query   string
+		options []Option
+		count   string
+		paged   string
+	}{
+		{
+			name:    "sqlserver",
+			query:   "SELECT id FROM users WHERE id > @p1 ORDER BY id",
+			options: []Option{WithDialect(SQLServer)},
+			count:   "SELECT COUNT(*) FROM (SELECT id FROM users WHERE id > @p1) counted",
+			paged:   "SELECT id FROM users WHERE id > @p1 ORDER BY id OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY",
+		},
+		{
+			name:    "sqlserver unordered",
+			query:   "SELECT id FROM users WHERE id > @p1",
+			options: []Option{WithDialect(SQLServer)},
+			count:   "SELECT COUNT(*) FROM (SELECT id FROM users WHERE id > @p1) counted",
+			paged:   "SELECT id FROM users WHERE id > @p1 ORDER BY (SELECT NULL) OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY",
+		},
+		{
+			name:    "sqlserver unordered window count",
+			query:   "SELECT id FROM users WHERE id IN (SELECT TOP 5 id FROM users ORDER BY id)",
+			options: []Option{WithDialect(SQLServer), WithWindowCount()},
+			count:   "SELECT COUNT(*) FROM (SELECT id FROM users WHERE id IN (SELECT TOP 5 id FROM users ORDER BY id)) counted",
+			paged:   "SELECT paged.*, COUNT(*) OVER() FROM (SELECT id FROM users WHERE id IN (SELECT TOP 5 id FROM users ORDER BY id)) paged ORDER BY (SELECT NULL) OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY",
+		},
+		{
+			name:    "sqlserver window count",
+			query:   "SELECT id FROM users WHERE id > @p1 ORDER BY id",
+			options: []Option{WithDialect(SQLServer), WithWindowCount()},
+			count:   "SELECT COUNT(*) FROM (SELECT id FROM users WHERE id > @p1) counted",
+			paged:   "SELECT paged.*, COUNT(*) OVER() FROM (SELECT id FROM users WHERE id > @p1) paged ORDER BY id OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY",
+		},
+		{
+			name:    "sqlserver window count with order by",
+			query:   "SELECT id FROM users WHERE id > @p1",
+			options: []Option{WithDialect(SQLServer), WithWindowCount(), WithOrderBy()},
+			count:   "SELECT COUNT(*) FROM (SELECT id FROM users WHERE id > @p1) counted",
+			paged:   "SELECT paged.*, COUNT(*) OVER() FROM (SELECT id FROM users WHERE id > @p1) paged ORDER BY [id] DESC OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY",
+		},
+		{
+			name:    "oracle window count",
+			query:   "SELECT id, ROW_NUMBER() OVER (ORDER BY id) rn /* ORDER BY id */ FROM users WHERE name <> 'ORDER BY' ORDER BY rn",
+			options: []Option{WithDialect(Oracle), WithWindowCount()},
+			count:   "SELECT COUNT(*) FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY id) rn /* ORDER BY id */ FROM users WHERE name <> 'ORDER BY') counted",
+			paged:   "SELECT paged.*, COUNT(*) OVER() FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY id) rn /* ORDER BY id */ FROM users WHERE name <> 'ORDER BY') paged ORDER BY rn OFFSET :2 ROWS FETCH NEXT :3 ROWS ONLY",
+		},
+		{
+			name:    "oracle with order by",
+			query:   "SELECT id FROM users WHERE id > :1",
+			options: []Option{WithDialect(Oracle), WithOrderBy()},
+			count:   "SELECT COUNT(*) FROM (SELECT id FROM users WHERE id > :1) counted",
+			paged:   `SELECT id FROM users WHERE id > :1 ORDER BY "id" DESC OFFSET :2 ROWS FETCH NEXT :3 ROWS ONLY`,
+		},
+	}
+	for _, test := range tests {
+		c := config{dialect: MySQL}
+		for _, option := range test.options {
+			option(&c)
+		}
+		query := test.query
+		if c.window {
+			query = windowQuery(query)
+		}
+		paged, args, err := paginate(c, page, query, []interface{}{1})
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.count, countQuery(test.query), test.name)
+		assert.Equal(t, test.paged, paged, test.name)
+		assert.Equal(t, []interface{}{1, int64(10), int64(10)}, args, test.name)
+	}
+}
@@ -0,0 +1,132 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// CheckpointStore persists the position of a Walker, i.e. the request of the
+// next page to process.
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, ok is false when there is none.
+	Load(ctx context.Context) (p Page, ok bool, err error)
+	Save(ctx context.Context, p Page) error
+	// Clear removes the checkpoint once the walk is complete.
+	Clear(ctx context.Context) error
+}
+
+// Walker processes a paginated source page by page and saves a checkpoint
+// after each page, so that a restarted job resumes after the last completed page.
+type Walker[T any] struct {
+	fetch   FetchFunc[T]
+	store   CheckpointStore
+	options []IteratorOption
+}
+
+func NewWalker[T any](fetch FetchFunc[T], store CheckpointStore, options ...IteratorOption) *Walker[T] {
+	return &Walker[T]{fetch: fetch, store: store, options: options}
+}
+
+// Walk calls fn for every page, starting at the checkpoint if there is one
+// and at start otherwise. A page whose fn returns an error is processed
+// again by the next Walk.
+func (w *Walker[T]) Walk(ctx context.Context, start Page, fn func(ctx context.Context, items []T, p Page) error) error {
+	var c iteratorConfig
+	for i := range w.options {
+		w.options[i](&c)
+	}
+
+	req, ok, err := w.store.Load(ctx)
+	if err != nil {
+		return errors.Wrap(err, "load checkpoint")
+	}
+	if !ok {
+		req = start
+	}
+	if req.Token != "" {
+		c.tokens = true
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		items, got, err := w.fetch(ctx, req)
+		if err != nil {
+			return errors.Wrapf(err, "fetch page %d", req.Num)
+		}
+		if err := fn(ctx, items, got); err != nil {
+			return err
+		}
+
+		next, more := c.following(req, got, len(items))
+		if !more {
+			return errors.Wrap(w.store.Clear(ctx), "clear checkpoint")
+		}
+		if err := w.store.Save(ctx, next); err != nil {
+			return errors.Wrap(err, "save checkpoint")
+		}
+		req = next
+	}
+}
+
+// FileCheckpointStore keeps the checkpoint as JSON in a file.
+type FileCheckpointStore struct {
+	path string
+}
+
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+func (s *FileCheckpointStore) Load(context.Context) (Page, bool, error) {
+	var p Page
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return p, false, nil
+	}
+	if err != nil {
+		return p, false, err
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, false, errors.Wrapf(err, "decode %s", s.path)
+	}
+	return p, true, nil
+}
+
+// Save replaces the file atomically, a crash never leaves a partial checkpoint.
+func (s *FileCheckpointStore) Save(_ context.Context, p Page) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *FileCheckpointStore) Clear(context.Context) error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
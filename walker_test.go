@@ -0,0 +1,56 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	store := NewFileCheckpointStore(path)
+	failed := errors.New("failed")
+
+	for _, tokens := range []bool{false, true} {
+		api := &fakeAPI{total: 45, withTotal: true}
+		fetch := api.byNumber
+		if tokens {
+			fetch = api.byToken
+		}
+
+		var processed []int
+		fail := true
+		process := func(ctx context.Context, items []int, p Page) error {
+			if items[0] == 21 && fail {
+				fail = false
+				return failed
+			}
+			processed = append(processed, items...)
+			return nil
+		}
+
+		walker := NewWalker(fetch, store)
+		err := walker.Walk(context.Background(), Page{Num: 1, Size: 10}, process)
+		assert.ErrorIs(t, err, failed)
+		assert.Len(t, processed, 20)
+
+		checkpoint, ok, err := store.Load(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, 3, checkpoint.Num)
+
+		// the restarted walk resumes at the failed page
+		api.requests = nil
+		assert.NoError(t, walker.Walk(context.Background(), Page{Num: 1, Size: 10}, process))
+		assert.Equal(t, api.items(), processed)
+		assert.Equal(t, 3, api.requests[0].Num)
+		assert.Len(t, api.requests, 3)
+
+		_, ok, err = store.Load(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	}
+}